@@ -0,0 +1,174 @@
+package skywalker
+
+import (
+	"container/list"
+	"github.com/skycoin/cxo/skyobject"
+	"reflect"
+	"sync"
+)
+
+// Cache is a bounded LRU cache of object values obtained from roots, keyed by dynamic reference (schema reference and
+// object reference), and of objects decoded into Go values, keyed by dynamic reference and Go type. Decoded objects
+// are copied into the walker's pointers, so that changes to them do not affect the cache.
+// As objects are content-addressed, cached values never become stale, and hence a cache can be shared between walkers
+// of roots of the same container. It is safe for concurrent use.
+type Cache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List                    // Front is most recently used.
+	items map[interface{}]*list.Element // Keyed by 'skyobject.Dynamic' or 'decodedKey'.
+	stats CacheStats
+}
+
+// CacheStats represents the statistics of a Cache.
+type CacheStats struct {
+	Hits         uint64 // Number of lookups that found a cached value.
+	Misses       uint64 // Number of lookups that did not find a cached value.
+	DecodeHits   uint64 // Number of decodes that found a cached decoded object.
+	DecodeMisses uint64 // Number of decodes that did not find a cached decoded object.
+	Evictions    uint64 // Number of values and decoded objects evicted to bound the size of the cache.
+	Len          int    // Number of values and decoded objects currently cached.
+}
+
+// decodedKey represents the key of an object decoded into a Go value of type 't'.
+type decodedKey struct {
+	dyn skyobject.Dynamic
+	t   reflect.Type
+}
+
+// cacheEntry represents a value, or a decoded object, of a Cache.
+type cacheEntry struct {
+	key   interface{}
+	value interface{} // Either '*skyobject.Value', or 'reflect.Value' of a decoded object.
+}
+
+// NewCache creates a cache that holds at most 'size' values.
+func NewCache(size int) *Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &Cache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[interface{}]*list.Element),
+	}
+}
+
+// Stats returns the statistics of the cache.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Len = c.ll.Len()
+	return stats
+}
+
+// Helper function. Obtains the cached value of 'dyn'.
+func (c *Cache) get(dyn skyobject.Dynamic) (*skyobject.Value, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, has := c.lookup(dyn)
+	if has == false {
+		c.stats.Misses++
+		return nil, false
+	}
+	c.stats.Hits++
+	return value.(*skyobject.Value), true
+}
+
+// Helper function. Caches value 'v' of 'dyn'.
+func (c *Cache) add(dyn skyobject.Dynamic, v *skyobject.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.insert(dyn, v)
+}
+
+// Helper function. Obtains the cached object of 'dyn' decoded into a Go value of type 't'.
+// The returned value is shared, and should be copied before it is changed.
+func (c *Cache) getDecoded(dyn skyobject.Dynamic, t reflect.Type) (reflect.Value, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, has := c.lookup(decodedKey{dyn: dyn, t: t})
+	if has == false {
+		c.stats.DecodeMisses++
+		return reflect.Value{}, false
+	}
+	c.stats.DecodeHits++
+	return value.(reflect.Value), true
+}
+
+// Helper function. Caches object 'v' of 'dyn' decoded into a Go value, which should not be changed afterwards.
+func (c *Cache) addDecoded(dyn skyobject.Dynamic, v reflect.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.insert(decodedKey{dyn: dyn, t: v.Type()}, v)
+}
+
+// Helper function. Obtains the cached value of 'key', marking it as most recently used. Requires the lock.
+func (c *Cache) lookup(key interface{}) (interface{}, bool) {
+	el, has := c.items[key]
+	if has == false {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*cacheEntry).value, true
+}
+
+// Helper function. Caches 'value' of 'key', evicting the least recently used value if the cache is full.
+// Requires the lock.
+func (c *Cache) insert(key, value interface{}) {
+	if el, has := c.items[key]; has {
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&cacheEntry{key: key, value: value})
+	if c.ll.Len() > c.size {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*cacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// Helper function. Obtains a deep copy of Go value 'v', so that changes to the copy do not affect 'v'.
+// Unexported fields of structs are not copied, as they are not decoded either.
+func deepCopy(v reflect.Value) reflect.Value {
+	cp := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := cp.Field(i); f.CanSet() {
+				f.Set(deepCopy(v.Field(i)))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			break
+		}
+		cp.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			break
+		}
+		cp.Set(reflect.MakeMap(v.Type()))
+		for _, k := range v.MapKeys() {
+			cp.SetMapIndex(deepCopy(k), deepCopy(v.MapIndex(k)))
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			break
+		}
+		cp.Set(reflect.New(v.Type().Elem()))
+		cp.Elem().Set(deepCopy(v.Elem()))
+	default:
+		cp.Set(v)
+	}
+	return cp
+}
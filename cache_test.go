@@ -0,0 +1,83 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/skyobject"
+	"testing"
+	"time"
+)
+
+// decodeCounter is an Observer that counts decodes.
+type decodeCounter struct {
+	decodes int
+}
+
+func (o *decodeCounter) ObserveLookup(dyn skyobject.Dynamic, d time.Duration, e error)   {}
+func (o *decodeCounter) ObserveDecode(size int, d time.Duration, e error)                { o.decodes++ }
+func (o *decodeCounter) ObserveFinder(schemaName string, chosen bool, d time.Duration)   {}
+func (o *decodeCounter) ObserveSave(ref skyobject.Reference, depth int, d time.Duration) {}
+func (o *decodeCounter) ObserveReplace(d time.Duration)                                  {}
+
+func TestCache(t *testing.T) {
+	pk, sk := genKeyPair()
+	client := newClient()
+	defer client.Close()
+	r := fillContainer1(client.Container(), pk, sk)
+	cache := NewCache(3)
+
+	// First walker misses, and second walker hits.
+	w1, _ := NewRootWalker(r, pk, sk, WithCache(cache))
+	advanceToBoard(t, w1, "Talk", &Board{})
+	if stats := cache.Stats(); stats.Hits != 0 || stats.Misses != 2 || stats.DecodeMisses != 1 || stats.Len != 3 {
+		t.Error("unexpected stats after first walker:", stats)
+	}
+	w2, _ := NewReadOnlyRootWalker(r, pk, WithCache(cache))
+	advanceToBoard(t, w2, "Talk", &Board{})
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Misses != 2 || stats.DecodeHits != 1 {
+		t.Error("unexpected stats after second walker:", stats)
+	}
+
+	// Cache is bounded.
+	thread := &Thread{}
+	e := w2.AdvanceFromRefsField("Threads", thread, func(v *skyobject.Value) (chosen bool) {
+		return false
+	})
+	if e != ErrObjNotFound {
+		t.Error("expected error", ErrObjNotFound, "got", e)
+	}
+	if stats := cache.Stats(); stats.Len != 3 || stats.Evictions != 2 {
+		t.Error("unexpected stats after eviction:", stats)
+	}
+}
+
+func TestCache_Decoded(t *testing.T) {
+	pk, sk := genKeyPair()
+	client := newClient()
+	defer client.Close()
+	r := fillContainer1(client.Container(), pk, sk)
+	cache, obs := NewCache(10), &decodeCounter{}
+
+	// First walker decodes the board, and changes it in place.
+	w1, _ := NewReadOnlyRootWalker(r, pk, WithCache(cache), WithObserver(obs))
+	board1 := &Board{}
+	advanceToBoard(t, w1, "Talk", board1)
+	if obs.decodes != 1 {
+		t.Fatal("expected 1 decode, got", obs.decodes)
+	}
+	thread := board1.Threads[0]
+	board1.Name = "Changed"
+	board1.Threads[0] = skyobject.Reference{}
+
+	// Second walker copies the decoded board from the cache, unaffected by the changes.
+	w2, _ := NewReadOnlyRootWalker(r, pk, WithCache(cache), WithObserver(obs))
+	board2 := &Board{}
+	advanceToBoard(t, w2, "Talk", board2)
+	if obs.decodes != 1 {
+		t.Error("expected cached board not to be decoded, got", obs.decodes, "decodes")
+	}
+	if board2.Name != "Talk" || board2.Threads[0] != thread {
+		t.Error("cached board was changed:", board2)
+	}
+	if stats := cache.Stats(); stats.DecodeHits != 1 || stats.DecodeMisses != 1 {
+		t.Error("unexpected stats:", stats)
+	}
+}
@@ -0,0 +1,156 @@
+package skywalker
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"io"
+	"strings"
+)
+
+// DOTOptions represents the options for WriteDOT.
+type DOTOptions struct {
+	// WholeRoot renders the whole root, instead of the subtree of the top-most object of the internal stack.
+	WholeRoot bool
+
+	// KeyFields are the names of fields shown in the labels of objects, keyed by schema name.
+	// All fields of basic kinds are shown for schemas without key fields.
+	KeyFields map[string][]string
+}
+
+// WriteDOT writes the graph of the root's objects (or of the top-most object's subtree) to 'out' in the Graphviz DOT
+// language. Each object is drawn once as a node labeled with it's schema name and key fields, and each reference is
+// drawn as an edge labeled with the field name (and index) holding it. Objects shared by multiple parents hence have
+// multiple incoming edges. The output is deterministic for a given root, so that outputs can be compared.
+func (w *RootWalker) WriteDOT(out io.Writer, opts DOTOptions) error {
+	// Check root.
+	r, e := w.root()
+	if e != nil {
+		return e
+	}
+
+	g := &dotGraph{
+		w:     w,
+		r:     r,
+		opts:  opts,
+		buf:   new(bytes.Buffer),
+		drawn: make(map[skyobject.Reference]bool),
+	}
+	g.buf.WriteString("digraph skywalker {\n")
+	g.buf.WriteString("\tnode [shape=box];\n")
+
+	if opts.WholeRoot {
+		g.buf.WriteString("\troot [label=\"Root\", shape=ellipse];\n")
+		for _, c := range rootChildRefs(r) {
+			fmt.Fprintf(g.buf, "\troot -> %q [label=\"Refs[%d]\"];\n", refHex(c.dyn.Object), c.index)
+			if e := g.draw(c.dyn); e != nil {
+				return e
+			}
+		}
+	} else {
+		// Obtain top-most object from internal stack.
+		obj, e := w.peek()
+		if e != nil {
+			return e
+		}
+		dyn, e := obj.dynamic()
+		if e != nil {
+			return e
+		}
+		if e := g.draw(dyn); e != nil {
+			return e
+		}
+	}
+
+	g.buf.WriteString("}\n")
+	_, e = out.Write(g.buf.Bytes())
+	return e
+}
+
+// dotGraph represents a graph being written in the DOT language.
+type dotGraph struct {
+	w     *RootWalker
+	r     *node.Root
+	opts  DOTOptions
+	buf   *bytes.Buffer
+	drawn map[skyobject.Reference]bool
+}
+
+// Helper function. Draws the object of 'dyn' and it's outgoing edges, then recursively draws it's children.
+// Objects that are already drawn are skipped.
+func (g *dotGraph) draw(dyn skyobject.Dynamic) error {
+	if g.drawn[dyn.Object] {
+		return nil
+	}
+	g.drawn[dyn.Object] = true
+
+	// Obtain value from root.
+	v, e := g.w.valueByDynamic(g.r, dyn)
+	if e != nil {
+		return e
+	}
+	id := refHex(dyn.Object)
+
+	// Draw node.
+	label, e := g.label(v)
+	if e != nil {
+		return e
+	}
+	fmt.Fprintf(g.buf, "\t%q [label=\"%s\"];\n", id, label)
+
+	// Draw edges.
+	children, e := childRefs(g.r, v)
+	if e != nil {
+		return e
+	}
+	for _, c := range children {
+		edge := c.fieldName
+		if c.index != -1 {
+			edge += fmt.Sprintf("[%d]", c.index)
+		}
+		fmt.Fprintf(g.buf, "\t%q -> %q [label=\"%s\"];\n", id, refHex(c.dyn.Object), dotEscape(edge))
+	}
+
+	// Draw children.
+	for _, c := range children {
+		if e := g.draw(c.dyn); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// Helper function. Obtains the label of a node; the schema name of value 'v', followed by it's key fields.
+func (g *dotGraph) label(v *skyobject.Value) (string, error) {
+	s := v.Schema()
+	lines := []string{dotEscape(s.Name())}
+
+	keyFields, has := g.opts.KeyFields[s.Name()]
+	if has == false {
+		for _, f := range s.Fields() {
+			if _, basic := basicKindTypes[f.Kind()]; basic {
+				keyFields = append(keyFields, f.Name())
+			}
+		}
+	}
+	for _, name := range keyFields {
+		fv, e := v.FieldByName(name)
+		if e != nil {
+			return "", e
+		}
+		pv, e := g.w.plainValue(fv)
+		if e != nil {
+			return "", e
+		}
+		lines = append(lines, dotEscape(fmt.Sprintf("%s: %v", name, pv)))
+	}
+	return strings.Join(lines, `\n`), nil
+}
+
+// Helper function. Escapes a string to be used within a quoted DOT string.
+func dotEscape(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return strings.Replace(s, "\n", `\n`, -1)
+}
@@ -0,0 +1,43 @@
+package skywalker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWalker_WriteDOT(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+
+	buf := new(bytes.Buffer)
+	e := w.WriteDOT(buf, DOTOptions{
+		WholeRoot: true,
+		KeyFields: map[string][]string{"Post": {"Title"}},
+	})
+	if e != nil {
+		t.Fatal("write dot failed:", e)
+	}
+	t.Log(buf.String())
+
+	// Shared objects are drawn once: root, 2 boards, 3 threads, 8 posts and 5 persons.
+	nodes, edges := 0, 0
+	for _, line := range strings.Split(buf.String(), "\n") {
+		switch {
+		case strings.Contains(line, "->"):
+			edges++
+		case strings.Contains(line, "[label="):
+			nodes++
+		}
+	}
+	if nodes != 19 {
+		t.Error("expected 19 nodes, got", nodes)
+	}
+	// Edges: 2 from root, 7 from boards, 10 from threads, 8 from posts.
+	if edges != 27 {
+		t.Error("expected 27 edges, got", edges)
+	}
+	if strings.Contains(buf.String(), `Body:`) {
+		t.Error("expected only key fields of posts")
+	}
+}
@@ -21,4 +21,61 @@ var (
 
 	// ErrFieldHasWrongType occurs when the field in question has an unexpected type.
 	ErrFieldHasWrongType = errors.New("field has wrong type")
+
+	// ErrNoSecretKey occurs when an action requires the root's secret key, but the walker was created without one.
+	ErrNoSecretKey = errors.New("no secret key provided")
+
+	// ErrKeyPairMismatch occurs when the walker's secret key does not belong to the root's public key.
+	ErrKeyPairMismatch = errors.New("secret key does not match public key")
+
+	// ErrRootKeyMismatch occurs when the root does not belong to the walker's public key.
+	ErrRootKeyMismatch = errors.New("root does not belong to public key")
+
+	// ErrRootReadOnly occurs when the root is published, but it was obtained without a secret key attached.
+	ErrRootReadOnly = errors.New("root has no secret key attached")
+
+	// ErrBadRootSignature occurs when the root's signature does not match the root's public key.
+	ErrBadRootSignature = errors.New("root has bad signature")
+
+	// ErrReadOnlyWalker occurs when a mutating action is performed on a walker that has no secret key.
+	ErrReadOnlyWalker = errors.New("walker is read-only")
+
+	// ErrSkipChildren is used as a return value from a Visitor to indicate that the children of the visited object
+	// are to be skipped. It is not returned as an error by any function.
+	ErrSkipChildren = errors.New("skip children of object")
+
+	// ErrStopWalk is used as a return value from a Visitor to indicate that the walk is to be stopped.
+	// It is not returned as an error by any function.
+	ErrStopWalk = errors.New("stop walk")
+
+	// ErrPathLengthMismatch occurs when the number of objects provided does not match the number of steps of a path.
+	ErrPathLengthMismatch = errors.New("number of objects does not match path length")
+
+	// ErrTypeNotProvided occurs when an action requires the Go type of a schema, but it was not provided with WithTypes.
+	ErrTypeNotProvided = errors.New("go type of schema not provided")
+
+	// ErrBadJSON occurs when a JSON document does not match the registered schemas.
+	ErrBadJSON = errors.New("json does not match schema")
+
+	// ErrNoParentFrame occurs when the top-most object of the internal stack has no parent object, as it is a direct
+	// child of the root.
+	ErrNoParentFrame = errors.New("top-most object has no parent object")
+
+	// ErrFrameNotDecoded occurs when a mutating action is performed while an object of the internal stack was advanced
+	// to lazily, and not yet deserialized with Decode.
+	ErrFrameNotDecoded = errors.New("object of internal stack is not decoded")
+
+	// ErrStackCleared occurs when the internal stack can not be restored along it's path after the root is changed, as
+	// the path no longer exists. The root is changed regardless, and the internal stack is cleared.
+	ErrStackCleared = errors.New("internal stack is cleared, as it's path no longer exists")
+
+	// ErrUnresolvedConflicts occurs when a merge is committed while some of it's conflicts are not resolved.
+	ErrUnresolvedConflicts = errors.New("merge has unresolved conflicts")
+
+	// ErrSchemaMismatch occurs when a Go type does not match the layout of a schema, or when a schema does not resolve
+	// in the registry of a root.
+	ErrSchemaMismatch = errors.New("schema does not match")
+
+	// ErrFuncNotProvided occurs when a function required by an action is nil.
+	ErrFuncNotProvided = errors.New("function not provided")
 )
@@ -0,0 +1,135 @@
+package skywalker
+
+import (
+	"encoding/json"
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"io"
+)
+
+// Names of the JSON fields that describe exported objects.
+const (
+	jsonSchemaField = "$schema" // Schema name of object.
+	jsonRefField    = "$ref"    // Hex of object's reference.
+	jsonPubField    = "$pub"    // Hex of root's public key.
+	jsonRefsField   = "Refs"    // Direct children of root.
+)
+
+// ExportOptions represents the options for ExportJSON.
+type ExportOptions struct {
+	// WholeRoot exports the whole root, instead of the top-most object of the internal stack.
+	WholeRoot bool
+
+	// Depth is the number of levels of referenced objects to inline. 0 inlines none, and negative inlines all.
+	Depth int
+
+	// Indent is the string used to indent nested JSON. Empty for compact output.
+	Indent string
+}
+
+// ExportJSON writes the top-most object of the internal stack (or the whole root) to 'out' as nested JSON.
+// Every object has a "$schema" field and a "$ref" field, with it's schema name and the hex of it's reference,
+// followed by it's fields as named in the registered schema. Objects are inlined as referenced objects up to the
+// depth given by 'opts', and objects that are not inlined have only the "$schema" and "$ref" fields.
+// Empty references are exported as null. The whole root is exported as an object with a "$pub" field, and a "Refs"
+// field holding the root's direct children.
+func (w *RootWalker) ExportJSON(out io.Writer, opts ExportOptions) error {
+	// Check root.
+	r, e := w.root()
+	if e != nil {
+		return e
+	}
+
+	var doc interface{}
+	if opts.WholeRoot {
+		refs := make([]interface{}, len(r.Refs()))
+		for i, dRef := range r.Refs() {
+			if refs[i], e = w.exportObj(r, dRef, opts.Depth); e != nil {
+				return e
+			}
+		}
+		doc = jsonObject{{jsonPubField, w.rpk.Hex()}, {jsonRefsField, refs}}
+	} else {
+		// Obtain top-most object from internal stack.
+		obj, e := w.peek()
+		if e != nil {
+			return e
+		}
+		dyn, e := obj.dynamic()
+		if e != nil {
+			return e
+		}
+		if doc, e = w.exportObj(r, dyn, opts.Depth); e != nil {
+			return e
+		}
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", opts.Indent)
+	return enc.Encode(doc)
+}
+
+// Helper function. Exports the object of dynamic reference 'dyn', inlining 'depth' levels of referenced objects.
+func (w *RootWalker) exportObj(r *node.Root, dyn skyobject.Dynamic, depth int) (interface{}, error) {
+	if dyn.Object == (skyobject.Reference{}) {
+		return nil, nil
+	}
+
+	// Obtain value from root.
+	v, e := w.valueByDynamic(r, dyn)
+	if e != nil {
+		return nil, e
+	}
+	out := jsonObject{{jsonSchemaField, v.Schema().Name()}, {jsonRefField, refHex(dyn.Object)}}
+
+	for _, f := range v.Schema().Fields() {
+		fv, e := v.FieldByName(f.Name())
+		if e != nil {
+			return nil, e
+		}
+		var value interface{}
+		switch kindOfField(f) {
+		case referencesField:
+			_, dyns, e := decodeReferences(r, f, fv)
+			if e != nil {
+				return nil, e
+			}
+			refs := make([]interface{}, len(dyns))
+			for i, dyn := range dyns {
+				if refs[i], e = w.exportRef(r, dyn, depth); e != nil {
+					return nil, e
+				}
+			}
+			value = refs
+		case referenceField, dynamicField:
+			dyn, e := decodeReference(r, f, fv)
+			if e != nil {
+				return nil, e
+			}
+			if value, e = w.exportRef(r, dyn, depth); e != nil {
+				return nil, e
+			}
+		default:
+			if value, e = w.plainValue(fv); e != nil {
+				return nil, e
+			}
+		}
+		out = append(out, jsonField{f.Name(), value})
+	}
+	return out, nil
+}
+
+// Helper function. Exports a referenced object; inlined if 'depth' allows, otherwise as it's schema and reference.
+func (w *RootWalker) exportRef(r *node.Root, dyn skyobject.Dynamic, depth int) (interface{}, error) {
+	if dyn.Object == (skyobject.Reference{}) {
+		return nil, nil
+	}
+	if depth != 0 {
+		return w.exportObj(r, dyn, depth-1)
+	}
+	s, e := r.SchemaByReference(dyn.Schema)
+	if e != nil {
+		return nil, e
+	}
+	return jsonObject{{jsonSchemaField, s.Name()}, {jsonRefField, refHex(dyn.Object)}}, nil
+}
@@ -0,0 +1,71 @@
+package skywalker
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWalker_ExportJSON(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+
+	advanceToBoard(t, w, "Talk", &Board{})
+
+	t.Run("top-most object", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if e := w.ExportJSON(buf, ExportOptions{Depth: 1, Indent: "  "}); e != nil {
+			t.Fatal("export failed:", e)
+		}
+		t.Log(buf.String())
+
+		var doc struct {
+			Schema  string `json:"$schema"`
+			Name    string
+			Creator struct {
+				Name string
+			}
+			Threads []struct {
+				Schema string `json:"$schema"`
+				Ref    string `json:"$ref"`
+				Name   string
+				Posts  []struct {
+					Schema string `json:"$schema"`
+					Ref    string `json:"$ref"`
+					Title  string
+				}
+			}
+		}
+		if e := json.Unmarshal(buf.Bytes(), &doc); e != nil {
+			t.Fatal("failed to decode exported JSON:", e)
+		}
+		if doc.Schema != "Board" || doc.Name != "Talk" || doc.Creator.Name != "Eric" {
+			t.Error("unexpected board:", doc.Schema, doc.Name, doc.Creator.Name)
+		}
+		if len(doc.Threads) != 2 || doc.Threads[0].Name != "Greetings" {
+			t.Fatal("unexpected threads:", doc.Threads)
+		}
+		// Posts are beyond depth, and are not inlined.
+		for _, post := range doc.Threads[0].Posts {
+			if post.Schema != "Post" || post.Ref == "" || post.Title != "" {
+				t.Error("expected post to not be inlined:", post)
+			}
+		}
+	})
+	t.Run("whole root", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if e := w.ExportJSON(buf, ExportOptions{WholeRoot: true, Depth: -1}); e != nil {
+			t.Fatal("export failed:", e)
+		}
+		var doc struct {
+			Pub  string `json:"$pub"`
+			Refs []json.RawMessage
+		}
+		if e := json.Unmarshal(buf.Bytes(), &doc); e != nil {
+			t.Fatal("failed to decode exported JSON:", e)
+		}
+		if doc.Pub != w.rpk.Hex() || len(doc.Refs) != 2 {
+			t.Error("unexpected root:", doc.Pub, len(doc.Refs))
+		}
+	})
+}
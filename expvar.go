@@ -0,0 +1,156 @@
+package skywalker
+
+import (
+	"bytes"
+	"expvar"
+	"fmt"
+	"github.com/skycoin/cxo/skyobject"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Upper bounds of the buckets of latency histograms.
+var latencyBuckets = []time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// latencyHistogram is an expvar.Var that counts durations in buckets.
+type latencyHistogram struct {
+	counts []int64 // Count of each bucket, and of durations above all buckets.
+	count  int64
+	sumNs  int64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{counts: make([]int64, len(latencyBuckets)+1)}
+}
+
+// Helper function. Adds a duration to the histogram.
+func (h *latencyHistogram) observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+	atomic.AddInt64(&h.counts[i], 1)
+	atomic.AddInt64(&h.count, 1)
+	atomic.AddInt64(&h.sumNs, int64(d))
+}
+
+// String implements expvar.Var. The histogram is represented as a JSON object of bucket counts keyed by their upper
+// bounds, along with the total count and sum of durations.
+func (h *latencyHistogram) String() string {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, bound := range latencyBuckets {
+		fmt.Fprintf(buf, "%q: %d, ", "le_"+bound.String(), atomic.LoadInt64(&h.counts[i]))
+	}
+	fmt.Fprintf(buf, "%q: %d, ", "le_inf", atomic.LoadInt64(&h.counts[len(latencyBuckets)]))
+	fmt.Fprintf(buf, "%q: %d, ", "count", atomic.LoadInt64(&h.count))
+	fmt.Fprintf(buf, "%q: %d", "sum_ns", atomic.LoadInt64(&h.sumNs))
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// ExpvarObserver is an Observer that exports counters and latency histograms of walker operations with the expvar
+// package. It is safe to share between walkers.
+type ExpvarObserver struct {
+	m *expvar.Map
+
+	lookups, lookupErrors *expvar.Int
+	decodes, decodeErrors *expvar.Int
+	decodedBytes          *expvar.Int
+	finders, chosen       *expvar.Int
+	saves, replaces       *expvar.Int
+	saveDepths            *expvar.Map
+
+	lookupLatency, decodeLatency, finderLatency *latencyHistogram
+	saveLatency, replaceLatency                 *latencyHistogram
+}
+
+// NewExpvarObserver creates an ExpvarObserver, and publishes it's variables as an expvar.Map named 'name'.
+// As with expvar.Publish, it panics if 'name' is already published.
+func NewExpvarObserver(name string) *ExpvarObserver {
+	o := &ExpvarObserver{
+		m:              new(expvar.Map).Init(),
+		lookups:        new(expvar.Int),
+		lookupErrors:   new(expvar.Int),
+		decodes:        new(expvar.Int),
+		decodeErrors:   new(expvar.Int),
+		decodedBytes:   new(expvar.Int),
+		finders:        new(expvar.Int),
+		chosen:         new(expvar.Int),
+		saves:          new(expvar.Int),
+		replaces:       new(expvar.Int),
+		saveDepths:     new(expvar.Map).Init(),
+		lookupLatency:  newLatencyHistogram(),
+		decodeLatency:  newLatencyHistogram(),
+		finderLatency:  newLatencyHistogram(),
+		saveLatency:    newLatencyHistogram(),
+		replaceLatency: newLatencyHistogram(),
+	}
+	o.m.Set("lookups", o.lookups)
+	o.m.Set("lookup_errors", o.lookupErrors)
+	o.m.Set("lookup_latency", o.lookupLatency)
+	o.m.Set("decodes", o.decodes)
+	o.m.Set("decode_errors", o.decodeErrors)
+	o.m.Set("decoded_bytes", o.decodedBytes)
+	o.m.Set("decode_latency", o.decodeLatency)
+	o.m.Set("finders", o.finders)
+	o.m.Set("finders_chosen", o.chosen)
+	o.m.Set("finder_latency", o.finderLatency)
+	o.m.Set("saves", o.saves)
+	o.m.Set("save_depths", o.saveDepths)
+	o.m.Set("save_latency", o.saveLatency)
+	o.m.Set("replaces", o.replaces)
+	o.m.Set("replace_latency", o.replaceLatency)
+	expvar.Publish(name, o.m)
+	return o
+}
+
+// ObserveLookup implements Observer.
+func (o *ExpvarObserver) ObserveLookup(dyn skyobject.Dynamic, d time.Duration, e error) {
+	o.lookups.Add(1)
+	if e != nil {
+		o.lookupErrors.Add(1)
+	}
+	o.lookupLatency.observe(d)
+}
+
+// ObserveDecode implements Observer.
+func (o *ExpvarObserver) ObserveDecode(size int, d time.Duration, e error) {
+	o.decodes.Add(1)
+	if e != nil {
+		o.decodeErrors.Add(1)
+	}
+	o.decodedBytes.Add(int64(size))
+	o.decodeLatency.observe(d)
+}
+
+// ObserveFinder implements Observer.
+func (o *ExpvarObserver) ObserveFinder(schemaName string, chosen bool, d time.Duration) {
+	o.finders.Add(1)
+	if chosen {
+		o.chosen.Add(1)
+	}
+	o.finderLatency.observe(d)
+}
+
+// ObserveSave implements Observer.
+func (o *ExpvarObserver) ObserveSave(ref skyobject.Reference, depth int, d time.Duration) {
+	o.saves.Add(1)
+	o.saveDepths.Add(strconv.Itoa(depth), 1)
+	o.saveLatency.observe(d)
+}
+
+// ObserveReplace implements Observer.
+func (o *ExpvarObserver) ObserveReplace(d time.Duration) {
+	o.replaces.Add(1)
+	o.replaceLatency.observe(d)
+}
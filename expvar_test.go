@@ -0,0 +1,42 @@
+package skywalker
+
+import (
+	"expvar"
+	"fmt"
+	"testing"
+)
+
+// Number of runs of TestExpvarObserver, as each run publishes it's variables under a unique name (e.g. with -count).
+var expvarTestRuns int
+
+func TestExpvarObserver(t *testing.T) {
+	expvarTestRuns++
+	name := fmt.Sprintf("skywalker_test_%d", expvarTestRuns)
+	obs := NewExpvarObserver(name)
+	client, w := newTestWalker(t, WithObserver(obs))
+	defer client.Close()
+
+	advanceToThread(t, w, "Talk", "Expressions", &Board{}, &Thread{})
+	if _, e := w.ReplaceInRefField("Creator", Person{"Donald Trump", 70}); e != nil {
+		t.Error("failed to replace:", e)
+	}
+
+	// 2 boards and 2 threads are looked up, decoding and choosing 1 of each.
+	if n := obs.lookups.Value(); n != 4 {
+		t.Error("expected 4 lookups, got", n)
+	}
+	if n := obs.decodes.Value(); n != 2 {
+		t.Error("expected 2 decodes, got", n)
+	}
+	if n, c := obs.finders.Value(), obs.chosen.Value(); n != 4 || c != 2 {
+		t.Error("expected 4 finder evaluations with 2 chosen, got", n, c)
+	}
+	// Person, thread and board are saved once each, and the root is replaced once.
+	if n := obs.saves.Value(); n != 3 {
+		t.Error("expected 3 saves, got", n)
+	}
+	if n := obs.replaces.Value(); n != 1 {
+		t.Error("expected 1 replace, got", n)
+	}
+	t.Log(expvar.Get(name))
+}
@@ -0,0 +1,156 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"github.com/skycoin/skycoin/src/cipher/encoder"
+	"reflect"
+	"strings"
+)
+
+// Name of the type of dynamic reference fields, as it appears in schemas.
+var dynamicTypeName = reflect.TypeOf(skyobject.Dynamic{}).Name()
+
+// Helper function. Obtains schema name from the 'skyobject' tag of a reference field.
+// Returns an empty string if the field has no schema tag.
+func schemaNameFromTag(tag reflect.StructTag) string {
+	return strings.TrimPrefix(tag.Get("skyobject"), "schema=")
+}
+
+// fieldKind represents the kind of a field, in terms of the references it holds.
+type fieldKind int
+
+const (
+	plainField      fieldKind = iota // Field that holds no references.
+	referencesField                  // Field of type 'skyobject.References' with a schema tag.
+	referenceField                   // Field of type 'skyobject.Reference' with a schema tag.
+	dynamicField                     // Field of type 'skyobject.Dynamic'.
+)
+
+// Helper function. Obtains the kind of field 'f'.
+func kindOfField(f skyobject.Field) fieldKind {
+	switch f.Kind() {
+	case reflect.Slice:
+		if schemaNameFromTag(f.Tag()) != "" {
+			return referencesField
+		}
+	case reflect.Array:
+		if schemaNameFromTag(f.Tag()) != "" {
+			return referenceField
+		}
+	case reflect.Struct:
+		if f.Schema().Name() == dynamicTypeName {
+			return dynamicField
+		}
+	}
+	return plainField
+}
+
+// childRef represents a reference to a child object, held in a field of it's parent object.
+type childRef struct {
+	fieldName string            // Field name of parent object. Empty if parent is the root.
+	index     int               // Index in parent's field. -1 if single reference (not array).
+	dyn       skyobject.Dynamic // Dynamic reference of child object.
+}
+
+// Helper function. Obtains the children of the root, in order.
+func rootChildRefs(r *node.Root) (refs []childRef) {
+	for i, dRef := range r.Refs() {
+		if dRef == (skyobject.Dynamic{}) {
+			continue
+		}
+		refs = append(refs, childRef{index: i, dyn: dRef})
+	}
+	return
+}
+
+// Helper function. Obtains the children of value 'v', in order of fields.
+// Fields of type 'skyobject.References' and 'skyobject.Reference' are only recognised when they have a schema tag,
+// and empty references are skipped.
+func childRefs(r *node.Root, v *skyobject.Value) (refs []childRef, e error) {
+	for _, f := range v.Schema().Fields() {
+		fRefs, e := fieldChildRefs(r, v, f)
+		if e != nil {
+			return nil, e
+		}
+		refs = append(refs, fRefs...)
+	}
+	return
+}
+
+// Helper function. Obtains the children of value 'v' that are referenced by field 'f'.
+// Returns nothing if the field does not hold references.
+func fieldChildRefs(r *node.Root, v *skyobject.Value, f skyobject.Field) (refs []childRef, e error) {
+	kind := kindOfField(f)
+	if kind == plainField {
+		return
+	}
+
+	// Obtain field's value.
+	fv, e := v.FieldByName(f.Name())
+	if e != nil {
+		return
+	}
+
+	switch kind {
+	case referencesField:
+		fRefs, dyns, e := decodeReferences(r, f, fv)
+		if e != nil {
+			return nil, e
+		}
+		for i, dyn := range dyns {
+			if fRefs[i] == (skyobject.Reference{}) {
+				continue
+			}
+			refs = append(refs, childRef{fieldName: f.Name(), index: i, dyn: dyn})
+		}
+	case referenceField, dynamicField:
+		dyn, e := decodeReference(r, f, fv)
+		if e != nil {
+			return nil, e
+		}
+		if dyn.Object == (skyobject.Reference{}) {
+			return nil, nil
+		}
+		refs = append(refs, childRef{fieldName: f.Name(), index: -1, dyn: dyn})
+	}
+	return
+}
+
+// Helper function. Decodes value 'fv' of field 'f' of type 'skyobject.References', and obtains a dynamic reference
+// for each of the references. The field is decoded without notifying the observer, as the helpers of this file are
+// also used without a walker (e.g. by NewReverseIndex), and only decode references rather than objects.
+func decodeReferences(r *node.Root, f skyobject.Field, fv *skyobject.Value) (
+	refs skyobject.References, dyns []skyobject.Dynamic, e error,
+) {
+	schema, e := r.SchemaByName(schemaNameFromTag(f.Tag()))
+	if e != nil {
+		return
+	}
+	if e = encoder.DeserializeRaw(fv.Data(), &refs); e != nil {
+		return
+	}
+	dyns = make([]skyobject.Dynamic, len(refs))
+	for i, ref := range refs {
+		dyns[i] = skyobject.Dynamic{Object: ref, Schema: schema.Reference()}
+	}
+	return
+}
+
+// Helper function. Decodes value 'fv' of field 'f' of type 'skyobject.Reference' or 'skyobject.Dynamic', and obtains
+// a dynamic reference. As with decodeReferences, the field is decoded without notifying the observer.
+func decodeReference(r *node.Root, f skyobject.Field, fv *skyobject.Value) (dyn skyobject.Dynamic, e error) {
+	if kindOfField(f) == dynamicField {
+		e = encoder.DeserializeRaw(fv.Data(), &dyn)
+		return
+	}
+	schema, e := r.SchemaByName(schemaNameFromTag(f.Tag()))
+	if e != nil {
+		return
+	}
+	if e = encoder.DeserializeRaw(fv.Data(), &dyn.Object); e != nil {
+		return
+	}
+	dyn.Schema = schema.Reference()
+	return
+}
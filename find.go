@@ -0,0 +1,58 @@
+package skywalker
+
+import "github.com/skycoin/cxo/skyobject"
+
+// FindOrder represents the order in which FindAll searches the root's tree.
+type FindOrder int
+
+const (
+	// DepthFirst searches the root's tree in depth-first order, the same order as Walk.
+	DepthFirst FindOrder = iota
+
+	// BreadthFirst searches the root's tree level by level, so that the shallowest matches are found first.
+	BreadthFirst
+)
+
+// FindOptions represents the options for FindAll.
+type FindOptions struct {
+	Limit int       // Maximum number of matches to return. 0 for unlimited.
+	Order FindOrder // Order of search, and hence of returned matches.
+}
+
+// FindAll searches the whole tree of the root for objects of schema 'schemaName' which are chosen by 'finder'.
+// The order of matches is deterministic for a given root. Objects referenced from multiple places are matched once
+// for each path. The path of each match can be used with Restore to advance the walker to the match.
+// The internal stack is not used nor changed.
+func (w *RootWalker) FindAll(schemaName string, finder func(v *skyobject.Value) bool, opts FindOptions) (
+	items []Item, e error,
+) {
+	// Check root.
+	r, e := w.root()
+	if e != nil {
+		return
+	}
+
+	// Helper function. Adds the item if it is a match, and stops the search when the limit is reached.
+	match := func(item Item) error {
+		if item.Value.Schema().Name() != schemaName || w.find(finder, item.Value) == false {
+			return nil
+		}
+		items = append(items, item)
+		if opts.Limit > 0 && len(items) >= opts.Limit {
+			return ErrStopWalk
+		}
+		return nil
+	}
+
+	switch opts.Order {
+	case DepthFirst:
+		e = w.Walk(func(path Path, s skyobject.Schema, v *skyobject.Value, depth int) error {
+			return match(Item{Path: path, Value: v})
+		})
+	case BreadthFirst:
+		e = w.walkLevels(r, LevelOptions{}, func(level int, item Item) error {
+			return match(item)
+		})
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/skyobject"
+	"testing"
+)
+
+func TestWalker_FindAll(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+
+	// Finds posts authored by Luis.
+	byLuis := func(v *skyobject.Value) bool {
+		fv, e := v.FieldByName("Author")
+		if e != nil {
+			return false
+		}
+		av, e := fv.Dereference()
+		if e != nil {
+			return false
+		}
+		nv, _ := av.FieldByName("Name")
+		s, _ := nv.String()
+		return s == "Luis"
+	}
+
+	t.Run("depth first", func(t *testing.T) {
+		items, e := w.FindAll("Post", byLuis, FindOptions{})
+		if e != nil {
+			t.Error("find all failed:", e)
+		}
+		if len(items) != 2 {
+			t.Error("expected 2 matches, got", len(items))
+		}
+		for _, item := range items {
+			t.Log(item.Path)
+		}
+	})
+	t.Run("breadth first with limit", func(t *testing.T) {
+		items, e := w.FindAll("Post", byLuis, FindOptions{Limit: 1, Order: BreadthFirst})
+		if e != nil {
+			t.Error("find all failed:", e)
+		}
+		if len(items) != 1 {
+			t.Fatal("expected 1 match, got", len(items))
+		}
+
+		// Restore onto match and mutate.
+		board, thread, post := &Board{}, &Thread{}, &Post{}
+		if e := w.Restore(items[0].Path, board, thread, post); e != nil {
+			t.Fatal("restore failed:", e)
+		}
+		if post.Title != "Howdy" && post.Title != "Is There?" {
+			t.Error("restored to wrong post:", post.Title)
+		}
+		if _, e := w.ReplaceInRefField("Author", Person{"Luis", 17}); e != nil {
+			t.Error("failed to replace:", e)
+		}
+		t.Log(w.String())
+	})
+}
@@ -0,0 +1,82 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/skyobject"
+	"github.com/skycoin/skycoin/src/cipher/encoder"
+	"reflect"
+)
+
+// Frame represents a snapshot of an object of the internal stack.
+// Frames are not affected by later changes to the walker, nor can they be used to change it.
+type Frame struct {
+	Depth     int                       // Depth in internal stack, where direct children of the root have a depth of 0.
+	Schema    string                    // Schema name of object.
+	SchemaRef skyobject.SchemaReference // Schema reference of object.
+	FieldName string                    // Field name of previous object used to find object. Empty if previous is the root.
+	Index     int                       // Index in previous object's field. -1 if single reference (not array).
+	Ref       skyobject.Reference       // Reference of object.
+	Value     interface{}               // Copy of deserialized object. Nil if the object is not decoded.
+}
+
+// Frames returns frames of all objects of the internal stack, from the root's direct child to the top-most object.
+func (w *RootWalker) Frames() []Frame {
+	frames := make([]Frame, w.Size())
+	for i := range w.stack {
+		frames[i] = w.frame(i)
+	}
+	return frames
+}
+
+// Current returns the frame of the top-most object of the internal stack.
+func (w *RootWalker) Current() (Frame, error) {
+	if w.Size() == 0 {
+		return Frame{}, ErrEmptyInternalStack
+	}
+	return w.frame(w.Size() - 1), nil
+}
+
+// Parent returns the frame of the object below the top-most object of the internal stack.
+func (w *RootWalker) Parent() (Frame, error) {
+	if w.Size() == 0 {
+		return Frame{}, ErrEmptyInternalStack
+	}
+	if w.Size() == 1 {
+		return Frame{}, ErrNoParentFrame
+	}
+	return w.frame(w.Size() - 2), nil
+}
+
+// Root returns the frame of the bottom-most object of the internal stack; the root's direct child.
+func (w *RootWalker) Root() (Frame, error) {
+	if w.Size() == 0 {
+		return Frame{}, ErrEmptyInternalStack
+	}
+	return w.frame(0), nil
+}
+
+// Helper function. Creates the frame of object 'i' of the internal stack.
+func (w *RootWalker) frame(i int) Frame {
+	obj := w.stack[i]
+	f := Frame{
+		Depth:     i,
+		SchemaRef: obj.s,
+		FieldName: obj.prevFieldName,
+		Index:     obj.prevInFieldIndex,
+	}
+	if s, _ := w.r.SchemaByReference(obj.s); s != nil {
+		f.Schema = s.Name()
+	}
+	if dyn, e := obj.dynamic(); e == nil {
+		f.Ref = dyn.Object
+	}
+
+	// Copy deserialized object, so that the frame is not affected by changes to the object.
+	if obj.p == nil {
+		return f
+	}
+	cp := reflect.New(obj.elem().Type())
+	if e := w.deserialize(encoder.Serialize(obj.p), cp.Interface()); e == nil {
+		f.Value = cp.Elem().Interface()
+	}
+	return f
+}
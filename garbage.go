@@ -0,0 +1,145 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+)
+
+// GarbageReport represents the objects that were replaced by the walker's mutations, and are no longer reachable.
+type GarbageReport struct {
+	Schemas map[string]*SchemaGarbage // Unreachable objects, keyed by schema name.
+	Count   int                       // Total number of unreachable objects.
+	Size    int                       // Total encoded size of unreachable objects, in bytes.
+}
+
+// SchemaGarbage represents the unreachable objects of a single schema.
+type SchemaGarbage struct {
+	Refs  []skyobject.Reference // References of unreachable objects.
+	Count int                   // Number of unreachable objects.
+	Size  int                   // Encoded size of unreachable objects, in bytes.
+}
+
+// Helper function. Records an object that is replaced by a mutation. Each object is recorded once.
+func (w *RootWalker) displace(dyn skyobject.Dynamic) {
+	if dyn == (skyobject.Dynamic{}) {
+		return
+	}
+	if _, has := w.displacedRefs[dyn.Object]; has {
+		return
+	}
+	if w.displacedRefs == nil {
+		w.displacedRefs = make(map[skyobject.Reference]struct{})
+	}
+	w.displacedRefs[dyn.Object] = struct{}{}
+	w.displaced = append(w.displaced, dyn)
+}
+
+// Garbage reports the objects that were replaced by the walker's mutations (including the previous versions of
+// ancestors that were re-saved, and the objects that only they referenced), which are no longer reachable from the
+// current root, nor from any of the 'retained' roots (e.g. historical versions of the root that are kept).
+// The objects still exist in the container, and the report can be used to remove them.
+// Replaced objects are recorded for as long as the walker lives, so callers should call ResetGarbage after handling the
+// report (e.g. once the objects are removed), for the records not to grow with every mutation.
+func (w *RootWalker) Garbage(retained ...*node.Root) (report *GarbageReport, e error) {
+	// Check root.
+	r, e := w.root()
+	if e != nil {
+		return
+	}
+
+	// Obtain all objects reachable from current and retained roots.
+	reachable := make(map[skyobject.Reference]struct{})
+	for _, rr := range append([]*node.Root{r}, retained...) {
+		if rr == nil {
+			continue
+		}
+		for _, c := range rootChildRefs(rr) {
+			if e = w.markReachable(rr, c.dyn, reachable); e != nil {
+				return nil, e
+			}
+		}
+	}
+
+	// Obtain unreachable objects from replaced objects and their descendants.
+	report = &GarbageReport{Schemas: make(map[string]*SchemaGarbage)}
+	seen := make(map[skyobject.Reference]struct{})
+	for _, dyn := range w.displaced {
+		if e = w.addGarbage(report, r, dyn, reachable, seen); e != nil {
+			return nil, e
+		}
+	}
+	return
+}
+
+// ResetGarbage forgets all replaced objects recorded by the walker, e.g. after they are removed from the container.
+func (w *RootWalker) ResetGarbage() {
+	w.displaced = nil
+	w.displacedRefs = nil
+}
+
+// Helper function. Adds the object of 'dyn' and it's descendants to 'report' if they are unreachable.
+// As objects that are reachable have reachable descendants, their descendants are not checked.
+func (w *RootWalker) addGarbage(report *GarbageReport, r *node.Root, dyn skyobject.Dynamic,
+	reachable, seen map[skyobject.Reference]struct{},
+) error {
+	if _, has := reachable[dyn.Object]; has {
+		return nil
+	}
+	if _, has := seen[dyn.Object]; has {
+		return nil
+	}
+	seen[dyn.Object] = struct{}{}
+
+	// Obtain value from root.
+	v, e := w.valueByDynamic(r, dyn)
+	if e != nil {
+		return e
+	}
+	schemaName := v.Schema().Name()
+	sg, has := report.Schemas[schemaName]
+	if has == false {
+		sg = &SchemaGarbage{}
+		report.Schemas[schemaName] = sg
+	}
+	sg.Refs = append(sg.Refs, dyn.Object)
+	sg.Count++
+	sg.Size += len(v.Data())
+	report.Count++
+	report.Size += len(v.Data())
+
+	// Check children.
+	children, e := childRefs(r, v)
+	if e != nil {
+		return e
+	}
+	for _, c := range children {
+		if e := w.addGarbage(report, r, c.dyn, reachable, seen); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// Helper function. Marks the object of 'dyn' and all it's descendants as reachable.
+func (w *RootWalker) markReachable(r *node.Root, dyn skyobject.Dynamic, reachable map[skyobject.Reference]struct{}) error {
+	if _, has := reachable[dyn.Object]; has {
+		return nil
+	}
+	reachable[dyn.Object] = struct{}{}
+
+	// Obtain value from root.
+	v, e := w.valueByDynamic(r, dyn)
+	if e != nil {
+		return e
+	}
+	children, e := childRefs(r, v)
+	if e != nil {
+		return e
+	}
+	for _, c := range children {
+		if e := w.markReachable(r, c.dyn, reachable); e != nil {
+			return e
+		}
+	}
+	return nil
+}
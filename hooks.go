@@ -0,0 +1,138 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"github.com/skycoin/skycoin/src/cipher"
+)
+
+// Mutation represents a change of the root made by the walker.
+type Mutation struct {
+	Op        string              // Name of the mutating method. E.g. "AppendToRefsField".
+	Path      Path                // Path of the top-most object. Empty if the mutation is not of the top-most object.
+	FieldName string              // Field name of the top-most object that is mutated.
+	OldRef    skyobject.Reference // Reference of the top-most object before the mutation.
+	NewRef    skyobject.Reference // Reference of the top-most object after the mutation. Empty before the mutation.
+	OldChild  skyobject.Reference // Reference of the child replaced in field FieldName. Empty for appends.
+	NewChild  skyobject.Reference // Reference of the new (or appended) child in FieldName. Empty before the mutation.
+	OldRoot   cipher.SHA256       // Hash of the root before the mutation.
+	NewRoot   cipher.SHA256       // Hash of the root after the mutation. Empty before the mutation.
+}
+
+// BeforeMutateHook is called before the walker mutates the root.
+// Returning an error vetoes the mutation, and the error is returned by the mutating method.
+type BeforeMutateHook func(m Mutation) error
+
+// AfterMutateHook is called after the walker mutates the root. It is not called if the mutation turns out to leave the
+// root unchanged.
+type AfterMutateHook func(m Mutation)
+
+// OnBeforeMutate registers a hook that is called before every mutation of the root, in order of registration.
+func (w *RootWalker) OnBeforeMutate(hook BeforeMutateHook) {
+	w.beforeHooks = append(w.beforeHooks, hook)
+}
+
+// OnAfterMutate registers a hook that is called after every mutation of the root, in order of registration.
+func (w *RootWalker) OnAfterMutate(hook AfterMutateHook) {
+	w.afterHooks = append(w.afterHooks, hook)
+}
+
+// Helper function. Performs mutation 'op' of field 'fieldName' of the top-most object with 'fn'.
+// All mutating methods of the top-most object should use this, as it fails fast if the walker is read-only, fires
+// hooks around the mutation, and publishes the root after it if required. 'fn' reports whether the root changed.
+// If 'fn' fails, the internal stack is restored, and the error of 'fn' is returned, unless the internal stack can not be
+// restored, in which case it is cleared and ErrStackCleared is returned.
+func (w *RootWalker) mutate(op, fieldName string, fn func(tObj *wrappedObj) (bool, error)) (bool, error) {
+	// Obtain top-most object.
+	tObj, e := w.peekMutable()
+	if e != nil {
+		return false, e
+	}
+	m := Mutation{Op: op, Path: w.Path(), FieldName: fieldName}
+	return w.runMutation(m, tObj, func() (bool, error) {
+		changed, e := fn(tObj)
+		if e != nil {
+			// The root is unchanged, but objects of the internal stack may be partially changed.
+			if e := w.restoreStack(); e != nil {
+				return changed, e
+			}
+		}
+		return changed, e
+	})
+}
+
+// Helper function. Performs mutation 'op' of the root with 'fn'. Behaves as 'mutate', but for mutations that are not
+// of the top-most object.
+func (w *RootWalker) mutateRoot(op string, fn func(r *node.Root) (bool, error)) error {
+	// Check root and walker.
+	if w.ReadOnly() {
+		return ErrReadOnlyWalker
+	}
+	r, e := w.root()
+	if e != nil {
+		return e
+	}
+	_, e = w.runMutation(Mutation{Op: op}, nil, func() (bool, error) {
+		return fn(r)
+	})
+	return e
+}
+
+// Helper function. Runs mutation 'fn' described by 'm', firing hooks around it. 'tObj' is the top-most object being
+// mutated, if any. If 'fn' reports that the root is unchanged, after-hooks are not fired and the root is not published.
+func (w *RootWalker) runMutation(m Mutation, tObj *wrappedObj, fn func() (bool, error)) (bool, error) {
+	m.OldRoot = cipher.SHA256(w.r.Hash())
+	if tObj != nil {
+		if dyn, e := tObj.dynamic(); e == nil {
+			m.OldRef = dyn.Object
+		}
+		m.OldChild = tObj.mutatedChild(m.FieldName, false)
+	}
+
+	// Fire before-hooks, which may veto the mutation.
+	for _, hook := range w.beforeHooks {
+		if e := hook(m); e != nil {
+			return false, e
+		}
+	}
+
+	// Mutate.
+	changed, e := fn()
+	if e != nil || changed == false {
+		return false, e
+	}
+
+	// Fire after-hooks.
+	m.NewRoot = cipher.SHA256(w.r.Hash())
+	if tObj != nil {
+		if dyn, e := tObj.dynamic(); e == nil {
+			m.NewRef = dyn.Object
+		}
+		m.NewChild = tObj.mutatedChild(m.FieldName, true)
+	}
+	for _, hook := range w.afterHooks {
+		hook(m)
+	}
+	return true, w.published()
+}
+
+// Helper function. Obtains the reference of the child in field 'fieldName' that a mutation replaces, or 'after' the
+// mutation, the reference that replaced it. As mutations of references fields append to them, the child of a
+// references field is the last reference after the mutation, and is empty before it.
+func (o *wrappedObj) mutatedChild(fieldName string, after bool) (ref skyobject.Reference) {
+	kind, e := o.getFieldKind(fieldName)
+	if e != nil {
+		return
+	}
+	switch kind {
+	case referencesField:
+		if refs, _, e := o.getFieldAsReferences(fieldName); e == nil && after && len(refs) > 0 {
+			ref = refs[len(refs)-1]
+		}
+	case referenceField, dynamicField:
+		if dyn, e := o.getChild(fieldName, -1); e == nil {
+			ref = dyn.Object
+		}
+	}
+	return
+}
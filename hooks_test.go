@@ -0,0 +1,67 @@
+package skywalker
+
+import (
+	"errors"
+	"github.com/skycoin/cxo/skyobject"
+	"testing"
+)
+
+func TestWalker_MutateHooks(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+
+	board := &Board{}
+	advanceToBoard(t, w, "Talk", board)
+
+	errVeto := errors.New("veto")
+	var mutations []Mutation
+	w.OnBeforeMutate(func(m Mutation) error {
+		if m.Op == "ReplaceInDynamicField" {
+			return errVeto
+		}
+		return nil
+	})
+	w.OnAfterMutate(func(m Mutation) {
+		mutations = append(mutations, m)
+	})
+
+	// Vetoed mutation does not change the root.
+	rDyns := w.r.Refs()
+	if _, e := w.ReplaceInDynamicField("Featured", Post{Title: "Vetoed"}); e != errVeto {
+		t.Error("expected error", errVeto, "got", e)
+	}
+	if w.r.Refs()[1] != rDyns[1] || len(mutations) != 0 {
+		t.Error("vetoed mutation changed the root")
+	}
+
+	// Allowed mutation fires after-hook.
+	oldCreator := board.Creator
+	if _, e := w.ReplaceInRefField("Creator", Person{"Donald Trump", 70}); e != nil {
+		t.Error("failed to replace:", e)
+	}
+	if len(mutations) != 1 {
+		t.Fatal("expected 1 mutation, got", len(mutations))
+	}
+	m := mutations[0]
+	if m.Op != "ReplaceInRefField" || m.FieldName != "Creator" || m.Path.String() != "Root.Refs[1]" {
+		t.Error("unexpected mutation:", m)
+	}
+	if m.OldRef != rDyns[1].Object || m.NewRef != w.r.Refs()[1].Object || m.OldRoot == m.NewRoot {
+		t.Error("unexpected references of mutation:", m)
+	}
+	if m.OldChild != oldCreator || m.NewChild != board.Creator {
+		t.Error("unexpected child references of mutation:", m)
+	}
+
+	// Appended child is reported as the new child.
+	if e := w.AppendToRefsField("Threads", Thread{Name: "Hooked"}); e != nil {
+		t.Error("failed to append:", e)
+	}
+	if len(mutations) != 2 {
+		t.Fatal("expected 2 mutations, got", len(mutations))
+	}
+	m = mutations[1]
+	if m.OldChild != (skyobject.Reference{}) || m.NewChild != board.Threads[len(board.Threads)-1] {
+		t.Error("unexpected child references of append:", m)
+	}
+}
@@ -0,0 +1,282 @@
+package skywalker
+
+import (
+	"encoding/json"
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"github.com/skycoin/skycoin/src/cipher"
+	"github.com/skycoin/skycoin/src/cipher/encoder"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Go types of reference fields.
+var (
+	referencesType = reflect.TypeOf(skyobject.References{})
+	referenceType  = reflect.TypeOf(skyobject.Reference{})
+	dynamicType    = reflect.TypeOf(skyobject.Dynamic{})
+)
+
+// ImportJSON reads a nested JSON document from 'in', in the format written by ExportJSON, and creates all it's
+// objects using the Go types provided with WithTypes. The created object is attached to field 'fieldName' of the
+// top-most object; appended if the field is of type 'skyobject.References', and replaced otherwise. If the internal
+// stack is empty, the created object is appended to the root's direct children. This changes the root once.
+//
+// Objects with fields are created, and objects with only a "$ref" field (and "$schema" field) reference existing
+// objects. The "$schema" field is required for objects of dynamic reference fields, and for the document itself when
+// it is attached to a dynamic reference field or to the root. Null represents an empty reference.
+// ErrBadJSON is returned for fields that are not fields of the object's Go type, and for a "$schema" field that does
+// not agree with the schema of the field holding the object. ErrObjNotFound is returned for references to objects that
+// do not exist in the container.
+func (w *RootWalker) ImportJSON(in io.Reader, fieldName string) error {
+	// Check root and walker.
+	if w.ReadOnly() {
+		return ErrReadOnlyWalker
+	}
+	if _, e := w.root(); e != nil {
+		return e
+	}
+
+	// Decode document.
+	var doc json.RawMessage
+	if e := json.NewDecoder(in).Decode(&doc); e != nil {
+		return e
+	}
+
+	// Obtain schema name of document from the field it is attached to.
+	var schemaName string
+	var fType reflect.Type
+	tObj, e := w.peekMutable()
+	switch e {
+	case nil:
+		ft, has := tObj.elem().Type().FieldByName(fieldName)
+		if has == false {
+			return ErrFieldNotFound
+		}
+		switch ft.Type {
+		case referencesType, referenceType:
+			schemaName = schemaNameFromTag(ft.Tag)
+		case dynamicType:
+		default:
+			return ErrFieldHasWrongType
+		}
+		fType = ft.Type
+	case ErrEmptyInternalStack:
+	default:
+		return e
+	}
+
+	// Create objects, and attach created object. Referenced objects are saved before the object is attached, so they
+	// are recorded as replaced objects if the import fails (e.g. the mutation is vetoed), for Garbage to report them.
+	var saved []skyobject.Dynamic
+	p, e := w.importObj(schemaName, doc, &saved)
+	if e == nil {
+		e = w.attachImported(fType, fieldName, p)
+	}
+	if e != nil {
+		for _, dyn := range saved {
+			w.displace(dyn)
+		}
+	}
+	return e
+}
+
+// Helper function. Attaches the imported object 'p' to the root, or to field 'fieldName' of type 'fType' of the
+// object at the top of the internal stack.
+func (w *RootWalker) attachImported(fType reflect.Type, fieldName string, p interface{}) (e error) {
+	switch fType {
+	case nil:
+		return w.mutateRoot("ImportJSON", func(r *node.Root) (bool, error) {
+			dyn, e := w.saveDynamic(p, -1)
+			if e != nil {
+				return false, e
+			}
+			w.replace(append(r.Refs(), dyn))
+			return true, nil
+		})
+	case referencesType:
+		return w.AppendToRefsField(fieldName, p)
+	case referenceType:
+		_, e = w.ReplaceInRefField(fieldName, p)
+		return e
+	default:
+		_, e = w.ReplaceInDynamicField(fieldName, p)
+		return e
+	}
+}
+
+// Helper function. Creates an object of schema 'schemaName' from JSON object 'doc', and returns a pointer to it.
+// All it's referenced objects are created and saved (and appended to 'saved'), but the object itself is not.
+// If 'schemaName' is empty, the schema name is obtained from the "$schema" field of 'doc'.
+func (w *RootWalker) importObj(schemaName string, doc json.RawMessage, saved *[]skyobject.Dynamic) (
+	interface{}, error,
+) {
+	// Decode fields.
+	var fields map[string]json.RawMessage
+	if e := json.Unmarshal(doc, &fields); e != nil || fields == nil {
+		return nil, ErrBadJSON
+	}
+	schemaName, e := jsonSchemaName(schemaName, fields)
+	if e != nil {
+		return nil, e
+	}
+	p, e := w.newObjOfSchema(schemaName)
+	if e != nil {
+		return nil, e
+	}
+
+	// Reference to existing object.
+	if isJSONRef(fields) {
+		dyn, e := w.importRef(schemaName, doc, saved)
+		if e != nil {
+			return nil, e
+		}
+		data, has := w.r.Get(dyn.Object)
+		if has == false {
+			return nil, ErrObjNotFound
+		}
+		if e := w.deserialize(data, p); e != nil {
+			return nil, e
+		}
+		return p, nil
+	}
+
+	// Set fields. All fields of the document, other than "$schema" and "$ref", are required to be fields of the object.
+	v := reflect.ValueOf(p).Elem()
+	vt := v.Type()
+	for name := range fields {
+		if _, has := vt.FieldByName(name); has == false && name != jsonSchemaField && name != jsonRefField {
+			return nil, ErrBadJSON
+		}
+	}
+	for i := 0; i < vt.NumField(); i++ {
+		ft := vt.Field(i)
+		raw, has := fields[ft.Name]
+		if has == false {
+			continue
+		}
+		f := v.Field(i)
+		fSchemaName := schemaNameFromTag(ft.Tag)
+
+		switch ft.Type {
+		case referencesType:
+			var docs []json.RawMessage
+			if e := json.Unmarshal(raw, &docs); e != nil {
+				return nil, ErrBadJSON
+			}
+			refs := make(skyobject.References, len(docs))
+			for j, doc := range docs {
+				dyn, e := w.importRef(fSchemaName, doc, saved)
+				if e != nil {
+					return nil, e
+				}
+				refs[j] = dyn.Object
+			}
+			f.Set(reflect.ValueOf(refs))
+		case referenceType:
+			dyn, e := w.importRef(fSchemaName, raw, saved)
+			if e != nil {
+				return nil, e
+			}
+			f.Set(reflect.ValueOf(dyn.Object))
+		case dynamicType:
+			dyn, e := w.importRef("", raw, saved)
+			if e != nil {
+				return nil, e
+			}
+			f.Set(reflect.ValueOf(dyn))
+		default:
+			if e := json.Unmarshal(raw, f.Addr().Interface()); e != nil {
+				return nil, ErrBadJSON
+			}
+		}
+	}
+	return p, nil
+}
+
+// Helper function. Obtains a dynamic reference of an object of schema 'schemaName' from JSON object 'doc'; creating
+// and saving the object (and appending it to 'saved') if 'doc' is not a reference to an existing object.
+// If 'schemaName' is empty, the schema name is obtained from the "$schema" field of 'doc'.
+func (w *RootWalker) importRef(schemaName string, doc json.RawMessage, saved *[]skyobject.Dynamic) (
+	dyn skyobject.Dynamic, e error,
+) {
+	// Empty reference.
+	if string(doc) == "null" {
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if e = json.Unmarshal(doc, &fields); e != nil || fields == nil {
+		e = ErrBadJSON
+		return
+	}
+	if schemaName, e = jsonSchemaName(schemaName, fields); e != nil {
+		return
+	}
+
+	// Reference to existing object.
+	if isJSONRef(fields) {
+		var refStr string
+		if e = json.Unmarshal(fields[jsonRefField], &refStr); e != nil {
+			e = ErrBadJSON
+			return
+		}
+		hash, e := cipher.SHA256FromHex(refStr)
+		if e != nil {
+			return dyn, ErrBadJSON
+		}
+		if _, has := w.r.Get(skyobject.Reference(hash)); has == false {
+			return dyn, ErrObjNotFound
+		}
+		schema, e := w.r.SchemaByName(schemaName)
+		if e != nil {
+			return dyn, e
+		}
+		return skyobject.Dynamic{Object: skyobject.Reference(hash), Schema: schema.Reference()}, nil
+	}
+
+	// Create and save object.
+	p, e := w.importObj(schemaName, doc, saved)
+	if e != nil {
+		return
+	}
+	if dyn, e = w.saveDynamic(p, -1); e != nil {
+		return
+	}
+	*saved = append(*saved, dyn)
+	return
+}
+
+// Helper function. Obtains the schema name of a JSON object with 'fields' from it's "$schema" field, which is required
+// to agree with 'schemaName' (e.g. the schema of the field the object is held by) if both are provided.
+func jsonSchemaName(schemaName string, fields map[string]json.RawMessage) (string, error) {
+	raw, has := fields[jsonSchemaField]
+	if has == false {
+		if schemaName == "" {
+			return "", ErrBadJSON
+		}
+		return schemaName, nil
+	}
+	var docSchemaName string
+	if e := json.Unmarshal(raw, &docSchemaName); e != nil || docSchemaName == "" {
+		return "", ErrBadJSON
+	}
+	if schemaName != "" && docSchemaName != schemaName {
+		return "", ErrBadJSON
+	}
+	return docSchemaName, nil
+}
+
+// Helper function. Checks whether the fields of a JSON object only reference an existing object.
+func isJSONRef(fields map[string]json.RawMessage) bool {
+	if _, has := fields[jsonRefField]; has == false {
+		return false
+	}
+	for name := range fields {
+		if strings.HasPrefix(name, "$") == false {
+			return false
+		}
+	}
+	return true
+}
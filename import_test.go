@@ -0,0 +1,120 @@
+package skywalker
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testTypes = map[string]interface{}{
+	"Person": Person{},
+	"Post":   Post{},
+	"Thread": Thread{},
+	"Board":  Board{},
+}
+
+func TestWalker_ImportJSON(t *testing.T) {
+	t.Run("append to field", func(t *testing.T) {
+		client, w := newTestWalker(t, WithTypes(testTypes))
+		defer client.Close()
+
+		advanceToBoard(t, w, "Talk", &Board{})
+
+		doc := `{
+			"Name": "Imported",
+			"Creator": {"Name": "Importer", "Age": 30},
+			"Posts": [
+				{"Title": "First", "Body": "Imported post.", "Author": {"Name": "Importer", "Age": 30}},
+				{"Title": "Second", "Body": "Another one.", "Author": null}
+			]
+		}`
+		if e := w.ImportJSON(strings.NewReader(doc), "Threads"); e != nil {
+			t.Fatal("import failed:", e)
+		}
+
+		thread := &Thread{}
+		if e := w.AdvanceFromRefsField("Threads", thread, findBy("Thread", "Name", "Imported")); e != nil {
+			t.Fatal("advance from board to imported thread failed:", e)
+		}
+		if len(thread.Posts) != 2 {
+			t.Error("expected 2 imported posts, got", len(thread.Posts))
+		}
+		post := &Post{}
+		if e := w.AdvanceFromRefsField("Posts", post, findBy("Post", "Title", "First")); e != nil {
+			t.Fatal("advance from thread to imported post failed:", e)
+		}
+		if post.Title != "First" || post.Author != thread.Creator {
+			t.Error("unexpected imported post:", post)
+		}
+	})
+	t.Run("bad documents", func(t *testing.T) {
+		client, w := newTestWalker(t, WithTypes(testTypes))
+		defer client.Close()
+
+		board := &Board{}
+		advanceToBoard(t, w, "Test", board)
+		missing := strings.Repeat("ab", 32)
+		docs := map[string]error{
+			`{"Name": "Typo", "Posts": [{"Titel": "Hi"}]}`:                        ErrBadJSON,
+			`{"Name": "Mismatch", "Creator": {"$schema": "Post", "Title": "Hi"}}`: ErrBadJSON,
+			`{"Name": "Dangling", "Creator": {"$ref": "` + missing + `"}}`:        ErrObjNotFound,
+		}
+		for doc, expected := range docs {
+			if e := w.ImportJSON(strings.NewReader(doc), "Threads"); e != expected {
+				t.Error("expected error", expected, "got", e, "for", doc)
+			}
+		}
+		if len(board.Threads) != 1 {
+			t.Error("expected no thread to be imported")
+		}
+	})
+	t.Run("vetoed import", func(t *testing.T) {
+		client, w := newTestWalker(t, WithTypes(testTypes))
+		defer client.Close()
+
+		advanceToBoard(t, w, "Test", &Board{})
+		errVeto := errors.New("veto")
+		w.OnBeforeMutate(func(m Mutation) error {
+			return errVeto
+		})
+
+		doc := `{
+			"Name": "Vetoed",
+			"Creator": {"Name": "Orphan", "Age": 1},
+			"Posts": [{"Title": "Orphaned", "Body": "Never attached.", "Author": {"Name": "Orphan", "Age": 1}}]
+		}`
+		if e := w.ImportJSON(strings.NewReader(doc), "Threads"); e != errVeto {
+			t.Fatal("expected error", errVeto, "got", e)
+		}
+
+		// Objects saved for the vetoed import are reported as garbage.
+		report, e := w.Garbage()
+		if e != nil {
+			t.Fatal("failed to obtain garbage:", e)
+		}
+		if report.Count != 2 || report.Schemas["Person"].Count != 1 || report.Schemas["Post"].Count != 1 {
+			t.Error("expected imported person and post to be garbage, got", report.Count, "objects")
+		}
+	})
+	t.Run("export and import", func(t *testing.T) {
+		client, w := newTestWalker(t, WithTypes(testTypes))
+		defer client.Close()
+
+		advanceToBoard(t, w, "Test", &Board{})
+		buf := new(bytes.Buffer)
+		if e := w.ExportJSON(buf, ExportOptions{Depth: 1}); e != nil {
+			t.Fatal("export failed:", e)
+		}
+
+		// Importing an exported board to the root creates an identical board.
+		w.Clear()
+		if e := w.ImportJSON(buf, ""); e != nil {
+			t.Fatal("import failed:", e)
+		}
+		rDyns := w.r.Refs()
+		if len(rDyns) != 3 || rDyns[2] != rDyns[0] {
+			t.Error("expected imported board to be identical to exported board")
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+)
+
+// ParentRef represents a field of a parent object (or the root) that references a child object.
+type ParentRef struct {
+	Parent    skyobject.Reference // Reference of parent object. Empty if parent is the root.
+	Schema    string              // Schema name of parent object. Empty if parent is the root.
+	FieldName string              // Field name of parent object. Empty if parent is the root.
+	Index     int                 // Index in parent's field. -1 if single reference (not array).
+}
+
+// IsRoot returns true if the parent is the root.
+func (p ParentRef) IsRoot() bool {
+	return p.Parent == (skyobject.Reference{})
+}
+
+// ReverseIndex maps the objects of a root's tree to the fields that reference them.
+// As objects are content-addressed, a single object may be referenced from many parents.
+type ReverseIndex struct {
+	parents map[skyobject.Reference][]ParentRef
+	schemas map[skyobject.Reference]string
+}
+
+// NewReverseIndex builds a reverse index over the whole tree of root 'r'.
+func NewReverseIndex(r *node.Root) (*ReverseIndex, error) {
+	if r == nil {
+		return nil, ErrRootNotFound
+	}
+	return newReverseIndex(r, r.ValueByDynamic)
+}
+
+// Helper function. Builds a reverse index over the whole tree of root 'r', obtaining values with 'lookup'.
+func newReverseIndex(r *node.Root, lookup func(dyn skyobject.Dynamic) (*skyobject.Value, error)) (
+	x *ReverseIndex, e error,
+) {
+	x = &ReverseIndex{
+		parents: make(map[skyobject.Reference][]ParentRef),
+		schemas: make(map[skyobject.Reference]string),
+	}
+	for _, c := range rootChildRefs(r) {
+		x.parents[c.dyn.Object] = append(x.parents[c.dyn.Object], ParentRef{Index: c.index})
+		if e = x.add(r, c.dyn, lookup); e != nil {
+			return nil, e
+		}
+	}
+	return
+}
+
+// ReverseIndex builds a reverse index over the whole tree of the walker's root.
+func (w *RootWalker) ReverseIndex() (*ReverseIndex, error) {
+	r, e := w.root()
+	if e != nil {
+		return nil, e
+	}
+	return newReverseIndex(r, func(dyn skyobject.Dynamic) (*skyobject.Value, error) {
+		return w.valueByDynamic(r, dyn)
+	})
+}
+
+// Helper function. Indexes the children of an object, and recursively, the children's children.
+// Objects that are already indexed are skipped, as their children are already indexed.
+func (x *ReverseIndex) add(r *node.Root, dyn skyobject.Dynamic,
+	lookup func(dyn skyobject.Dynamic) (*skyobject.Value, error),
+) error {
+	if _, has := x.schemas[dyn.Object]; has {
+		return nil
+	}
+
+	// Obtain value from root.
+	v, e := lookup(dyn)
+	if e != nil {
+		return e
+	}
+	schemaName := v.Schema().Name()
+	x.schemas[dyn.Object] = schemaName
+
+	// Index children.
+	children, e := childRefs(r, v)
+	if e != nil {
+		return e
+	}
+	for _, c := range children {
+		x.parents[c.dyn.Object] = append(x.parents[c.dyn.Object], ParentRef{
+			Parent:    dyn.Object,
+			Schema:    schemaName,
+			FieldName: c.fieldName,
+			Index:     c.index,
+		})
+		if e := x.add(r, c.dyn, lookup); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// Has returns true if the object of reference 'ref' is in the root's tree.
+func (x *ReverseIndex) Has(ref skyobject.Reference) bool {
+	_, has := x.schemas[ref]
+	return has
+}
+
+// SchemaName returns the schema name of the object of reference 'ref'.
+// Returns an empty string if the object is not in the root's tree.
+func (x *ReverseIndex) SchemaName(ref skyobject.Reference) string {
+	return x.schemas[ref]
+}
+
+// Parents returns all the fields that reference the object of reference 'ref'.
+func (x *ReverseIndex) Parents(ref skyobject.Reference) []ParentRef {
+	return x.parents[ref]
+}
+
+// ParentsOfSchema returns the fields of objects of schema 'schemaName' that reference the object of reference 'ref'.
+// E.g. the posts that are authored by a person.
+func (x *ReverseIndex) ParentsOfSchema(ref skyobject.Reference, schemaName string) (parents []ParentRef) {
+	for _, p := range x.parents[ref] {
+		if p.Schema == schemaName {
+			parents = append(parents, p)
+		}
+	}
+	return
+}
+
+// Paths returns all the paths from the root to the object of reference 'ref'.
+// The paths can be used with Restore to advance the walker to the object.
+func (x *ReverseIndex) Paths(ref skyobject.Reference) (paths []Path) {
+	schemaName := x.schemas[ref]
+	for _, p := range x.parents[ref] {
+		if p.IsRoot() {
+			paths = append(paths, Path{}.extend("", p.Index, schemaName))
+			continue
+		}
+		for _, prevPath := range x.Paths(p.Parent) {
+			paths = append(paths, prevPath.extend(p.FieldName, p.Index, schemaName))
+		}
+	}
+	return
+}
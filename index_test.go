@@ -0,0 +1,57 @@
+package skywalker
+
+import "testing"
+
+func TestReverseIndex(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+	r := w.r
+
+	x, e := w.ReverseIndex()
+	if e != nil {
+		t.Fatal("failed to build reverse index:", e)
+	}
+
+	// Luis is the creator of board "Test" and thread "Testing", and the author of two posts.
+	luis := r.Save(Person{"Luis", 16})
+	if x.Has(luis) == false {
+		t.Fatal("expected Luis to be indexed")
+	}
+	if n := len(x.Parents(luis)); n != 4 {
+		t.Error("expected 4 parents, got", n)
+	}
+	if n := len(x.ParentsOfSchema(luis, "Post")); n != 2 {
+		t.Error("expected 2 posts, got", n)
+	}
+
+	// Every path to Luis should restore to Luis.
+	for _, path := range x.Paths(luis) {
+		t.Log(path)
+		ps := make([]interface{}, len(path))
+		for i, step := range path {
+			switch step.Schema {
+			case "Board":
+				ps[i] = &Board{}
+			case "Thread":
+				ps[i] = &Thread{}
+			case "Post":
+				ps[i] = &Post{}
+			case "Person":
+				ps[i] = &Person{}
+			}
+		}
+		if e := w.Restore(path, ps...); e != nil {
+			t.Error("failed to restore path:", e)
+		}
+		if p := ps[len(ps)-1].(*Person); p.Name != "Luis" {
+			t.Error("restored to wrong person:", p.Name)
+		}
+	}
+
+	// Roots' direct children have the root as parent.
+	for _, dRef := range r.Refs() {
+		if ps := x.Parents(dRef.Object); len(ps) != 1 || ps[0].IsRoot() == false {
+			t.Error("expected root as only parent")
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+)
+
+// Item represents an object of the root's tree, along with the path used to reach it.
+type Item struct {
+	Path  Path
+	Value *skyobject.Value
+}
+
+// StopRule decides whether the children of an object should not be walked.
+type StopRule func(v *skyobject.Value) bool
+
+// LevelOptions represents the options for walking a root's tree level by level.
+type LevelOptions struct {
+	// MaxLevels is the maximum number of levels to walk, where direct children of the root form the first level.
+	// 0 for unlimited.
+	MaxLevels int
+
+	// MaxChildren is the maximum number of children to follow per field of type 'skyobject.References'.
+	// 0 for unlimited.
+	MaxChildren int
+
+	// StopRules are rules keyed by schema name. The children of objects of a schema are not walked if the schema's
+	// rule returns true. A nil rule always stops.
+	StopRules map[string]StopRule
+}
+
+// WalkLevels walks the root's tree in breadth-first order, and returns the objects grouped by level.
+// Objects referenced from multiple places appear once for each path.
+// The internal stack is not used nor changed.
+func (w *RootWalker) WalkLevels(opts LevelOptions) (levels [][]Item, e error) {
+	// Check root.
+	r, e := w.root()
+	if e != nil {
+		return
+	}
+
+	e = w.walkLevels(r, opts, func(level int, item Item) error {
+		if level == len(levels) {
+			levels = append(levels, nil)
+		}
+		levels[level] = append(levels[level], item)
+		return nil
+	})
+	return
+}
+
+// Helper function. Walks the tree of root 'r' in breadth-first order, calling 'fn' for each object.
+// Returning ErrStopWalk from 'fn' stops the walk without error.
+func (w *RootWalker) walkLevels(r *node.Root, opts LevelOptions, fn func(level int, item Item) error) error {
+	type queued struct {
+		prevPath Path
+		c        childRef
+	}
+
+	// Queue direct children of root.
+	var queue []queued
+	for _, c := range rootChildRefs(r) {
+		queue = append(queue, queued{Path{}, c})
+	}
+
+	for level := 0; len(queue) > 0 && (opts.MaxLevels == 0 || level < opts.MaxLevels); level++ {
+		var next []queued
+		for _, q := range queue {
+			// Obtain value from root.
+			v, e := w.valueByDynamic(r, q.c.dyn)
+			if e != nil {
+				return e
+			}
+			s := v.Schema()
+			path := q.prevPath.extend(q.c.fieldName, q.c.index, s.Name())
+			switch e := fn(level, Item{Path: path, Value: v}); e {
+			case nil:
+			case ErrStopWalk:
+				return nil
+			default:
+				return e
+			}
+
+			// Apply stop rule.
+			if rule, has := opts.StopRules[s.Name()]; has && (rule == nil || rule(v)) {
+				continue
+			}
+
+			// Queue children.
+			children, e := childRefs(r, v)
+			if e != nil {
+				return e
+			}
+			followed := make(map[string]int)
+			for _, c := range children {
+				if c.index != -1 && opts.MaxChildren > 0 {
+					if followed[c.fieldName] >= opts.MaxChildren {
+						continue
+					}
+					followed[c.fieldName]++
+				}
+				next = append(next, queued{path, c})
+			}
+		}
+		queue = next
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package skywalker
+
+import "testing"
+
+func TestWalker_WalkLevels(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+
+	levels, e := w.WalkLevels(LevelOptions{
+		MaxLevels:   3,
+		MaxChildren: 1,
+		StopRules: map[string]StopRule{
+			"Post":   nil,
+			"Thread": findBy("Thread", "Name", "Testing"),
+		},
+	})
+	if e != nil {
+		t.Error("walk levels failed:", e)
+	}
+	if len(levels) != 3 {
+		t.Fatal("expected 3 levels, got", len(levels))
+	}
+	for i, level := range levels {
+		for _, item := range level {
+			t.Log(i, item.Path)
+		}
+	}
+	// Level 0: 2 boards.
+	// Level 1: board creators, featured objects and first thread of each board.
+	if len(levels[0]) != 2 || len(levels[1]) != 6 {
+		t.Error("unexpected level sizes:", len(levels[0]), len(levels[1]))
+	}
+	// Level 2: creator and first post of "Greetings", but nothing of stopped "Testing", nor of featured post.
+	for _, item := range levels[2] {
+		if item.Path[1].Schema == "Post" {
+			t.Error("children of post should not be walked:", item.Path)
+		}
+	}
+	if len(levels[2]) != 2 {
+		t.Error("expected 2 objects in level 2, got", len(levels[2]))
+	}
+}
@@ -0,0 +1,387 @@
+package skywalker
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+)
+
+// Resolution represents the side chosen to resolve a conflict of a merge.
+type Resolution int
+
+const (
+	Unresolved    Resolution = iota // Conflict is not resolved.
+	ResolveOurs                     // Conflict is resolved with our version.
+	ResolveTheirs                   // Conflict is resolved with their version.
+)
+
+// Conflict represents a field that is changed differently by both sides of a merge.
+type Conflict struct {
+	Path      Path             // Path of the object in our root, whose field conflicts. Empty for the root's references.
+	FieldName string           // Field name of the object. Empty for the root's references.
+	Index     int              // Index in the references field (or the root's references). -1 if not an element.
+	Base      *skyobject.Value // Value of the field (or of the referenced object) in the base root. Nil if absent.
+	Ours      *skyobject.Value // Value of the field (or of the referenced object) in our root. Nil if absent.
+	Theirs    *skyobject.Value // Value of the field (or of the referenced object) in their root. Nil if absent.
+
+	Resolution Resolution // Resolution of the conflict, to be set before committing the merge.
+}
+
+// String returns the location of the conflict, e.g. `Root.Refs[0] > Board.Featured > Post.Title`.
+func (c *Conflict) String() string {
+	return c.Path.extend(c.FieldName, c.Index, "").String()
+}
+
+// Helper function. Obtains the key that identifies the conflict between runs of a merge.
+func (c *Conflict) key() string {
+	return fmt.Sprintf("%s|%s|%d", c.Path, c.FieldName, c.Index)
+}
+
+// Merge represents a three-way merge of the walker's root ('ours') with another root ('theirs') that diverged from
+// a common ancestor ('base'). It is obtained with Merge, and it's conflicts are to be resolved before Commit.
+type Merge struct {
+	w      *RootWalker
+	base   *node.Root
+	theirs *node.Root
+
+	Conflicts []*Conflict // Conflicts of the merge, in order of the tree.
+}
+
+// Merge merges the walker's root with root 'theirs', where both are new versions of root 'base'. Objects changed on a
+// single side are taken from that side. Objects changed on both sides are merged field by field, where references
+// fields are merged element-wise by index, keeping the elements appended by either side. Fields that are changed
+// differently by both sides are reported as conflicts, to be resolved before the merge is committed with Commit.
+// All three roots are required to be in the same container, and the Go types of all merged objects are required to
+// be provided with WithTypes. The walker's root is not changed until Commit.
+func (w *RootWalker) Merge(base, theirs *node.Root) (*Merge, error) {
+	// Check roots.
+	r, e := w.root()
+	if e != nil {
+		return nil, e
+	}
+	if base == nil || theirs == nil {
+		return nil, ErrRootNotFound
+	}
+
+	// Find conflicts, without saving merged objects.
+	m := &Merge{w: w, base: base, theirs: theirs}
+	ms := &mergeState{w: w, base: base, ours: r, theirs: theirs, dryRun: true}
+	if _, e := ms.mergeRoot(); e != nil {
+		return nil, e
+	}
+	m.Conflicts = ms.conflicts
+	return m, nil
+}
+
+// Resolved returns true if all conflicts of the merge are resolved.
+func (m *Merge) Resolved() bool {
+	for _, c := range m.Conflicts {
+		if c.Resolution == Unresolved {
+			return false
+		}
+	}
+	return true
+}
+
+// Commit merges the roots with the resolutions of the conflicts, and replaces the walker's root references with the
+// merged references. If the walker's root changed since Merge, and the merge has new conflicts,
+// ErrUnresolvedConflicts is returned. The internal stack is restored along the same path afterwards; if the path no
+// longer exists in the merged root, the internal stack is cleared and ErrStackCleared is returned.
+func (m *Merge) Commit() error {
+	w := m.w
+	resolutions := make(map[string]Resolution, len(m.Conflicts))
+	for _, c := range m.Conflicts {
+		resolutions[c.key()] = c.Resolution
+	}
+	var restoreErr error
+	e := w.mutateRoot("Merge", func(r *node.Root) (bool, error) {
+		ms := &mergeState{w: w, base: m.base, ours: r, theirs: m.theirs, resolutions: resolutions}
+		rDyns, e := ms.mergeRoot()
+		if e != nil {
+			return false, e
+		}
+		for _, c := range ms.conflicts {
+			if c.Resolution == Unresolved {
+				return false, ErrUnresolvedConflicts
+			}
+		}
+		if dynamicsEqual(rDyns, r.Refs()) {
+			return false, nil
+		}
+		w.replace(rDyns)
+		restoreErr = w.restoreStack()
+		return true, nil
+	})
+	if e != nil {
+		return e
+	}
+	return restoreErr
+}
+
+// mergeState represents a single run of a merge.
+type mergeState struct {
+	w                  *RootWalker
+	base, ours, theirs *node.Root
+
+	dryRun      bool                  // Merged objects are not saved when only conflicts are needed.
+	resolutions map[string]Resolution // Resolutions of conflicts from a previous run.
+	conflicts   []*Conflict
+}
+
+// Helper function. Records conflict 'c', and obtains it's resolution from a previous run.
+func (ms *mergeState) conflict(c *Conflict) Resolution {
+	c.Resolution = ms.resolutions[c.key()]
+	ms.conflicts = append(ms.conflicts, c)
+	return c.Resolution
+}
+
+// Helper function. Obtains the value of 'dyn' from root 'r'. Returns nil if the reference is empty.
+func (ms *mergeState) value(r *node.Root, dyn skyobject.Dynamic) (*skyobject.Value, error) {
+	if dyn.Object == (skyobject.Reference{}) {
+		return nil, nil
+	}
+	return ms.w.valueByDynamic(r, dyn)
+}
+
+// Helper function. Merges the references of the roots.
+func (ms *mergeState) mergeRoot() ([]skyobject.Dynamic, error) {
+	return ms.mergeList(nil, "", ms.base.Refs(), ms.ours.Refs(), ms.theirs.Refs(), nil, nil, nil)
+}
+
+// Helper function. Merges the elements of a references field 'fieldName' of the object of 'path' by index. Elements
+// appended by both sides are kept, ours first. Values 'bfv', 'ofv' and 'tfv' are the field's values, for conflicts.
+func (ms *mergeState) mergeList(path Path, fieldName string, b, o, t []skyobject.Dynamic,
+	bfv, ofv, tfv *skyobject.Value,
+) (out []skyobject.Dynamic, e error) {
+	// Merge common elements.
+	n := len(b)
+	if len(o) < n {
+		n = len(o)
+	}
+	if len(t) < n {
+		n = len(t)
+	}
+	for i := 0; i < n; i++ {
+		dyn, e := ms.mergeChild(path, fieldName, i, b[i], o[i], t[i])
+		if e != nil {
+			return nil, e
+		}
+		out = append(out, dyn)
+	}
+
+	// Merge remaining elements.
+	bTail, oTail, tTail := b[n:], o[n:], t[n:]
+	switch {
+	case dynamicsEqual(oTail, tTail), dynamicsEqual(bTail, tTail):
+		out = append(out, oTail...)
+	case dynamicsEqual(bTail, oTail):
+		out = append(out, tTail...)
+	case len(bTail) == 0:
+		out = append(out, oTail...)
+		for _, dyn := range tTail {
+			if containsDynamic(oTail, dyn) == false {
+				out = append(out, dyn)
+			}
+		}
+	default:
+		c := &Conflict{Path: path, FieldName: fieldName, Index: -1, Base: bfv, Ours: ofv, Theirs: tfv}
+		if ms.conflict(c) == ResolveTheirs {
+			out = append(out, tTail...)
+		} else {
+			out = append(out, oTail...)
+		}
+	}
+	return
+}
+
+// Helper function. Merges the child of index 'i' of field 'fieldName' of the object of 'path'. If the child can not be
+// merged, the field conflicts.
+func (ms *mergeState) mergeChild(path Path, fieldName string, i int, b, o, t skyobject.Dynamic) (
+	skyobject.Dynamic, error,
+) {
+	switch {
+	case o == t, b == t:
+		return o, nil
+	case b == o:
+		return t, nil
+	}
+
+	// Both sides changed the child. Obtain values.
+	bv, e := ms.value(ms.base, b)
+	if e != nil {
+		return o, e
+	}
+	ov, e := ms.value(ms.ours, o)
+	if e != nil {
+		return o, e
+	}
+	tv, e := ms.value(ms.theirs, t)
+	if e != nil {
+		return o, e
+	}
+
+	// Merge objects of the same schema by field. Otherwise, the field conflicts.
+	if ov != nil && tv != nil && o.Schema == t.Schema {
+		if b.Schema != o.Schema {
+			bv = nil
+		}
+		return ms.mergeObj(path.extend(fieldName, i, ov.Schema().Name()), o, bv, ov, tv)
+	}
+	c := &Conflict{Path: path, FieldName: fieldName, Index: i, Base: bv, Ours: ov, Theirs: tv}
+	if ms.conflict(c) == ResolveTheirs {
+		return t, nil
+	}
+	return o, nil
+}
+
+// Helper function. Merges the fields of object 'o' of 'path', that both sides changed. Value 'bv' is nil if the object
+// has no base.
+func (ms *mergeState) mergeObj(path Path, o skyobject.Dynamic, bv, ov, tv *skyobject.Value) (
+	nDyn skyobject.Dynamic, e error,
+) {
+	// Decode both sides.
+	schemaName := ov.Schema().Name()
+	po, e := ms.w.newObjOfSchema(schemaName)
+	if e != nil {
+		return
+	}
+	if e = ms.w.deserialize(ov.Data(), po); e != nil {
+		return
+	}
+	pt, e := ms.w.newObjOfSchema(schemaName)
+	if e != nil {
+		return
+	}
+	if e = ms.w.deserialize(tv.Data(), pt); e != nil {
+		return
+	}
+	oObj := &wrappedObj{s: o.Schema, p: po, v: ov, w: ms.w}
+	tObj := &wrappedObj{s: o.Schema, p: pt, v: tv, w: ms.w}
+
+	// Merge fields.
+	changed := false
+	for _, f := range ov.Schema().Fields() {
+		ofv, e := ov.FieldByName(f.Name())
+		if e != nil {
+			return nDyn, e
+		}
+		tfv, e := tv.FieldByName(f.Name())
+		if e != nil {
+			return nDyn, e
+		}
+		var bfv *skyobject.Value
+		if bv != nil {
+			if bfv, e = bv.FieldByName(f.Name()); e != nil {
+				return nDyn, e
+			}
+		}
+
+		switch kindOfField(f) {
+		case plainField:
+			switch {
+			case bytes.Equal(ofv.Data(), tfv.Data()), bfv != nil && bytes.Equal(bfv.Data(), tfv.Data()):
+				continue
+			case bfv != nil && bytes.Equal(bfv.Data(), ofv.Data()):
+			default:
+				c := &Conflict{Path: path, FieldName: f.Name(), Index: -1, Base: bfv, Ours: ofv, Theirs: tfv}
+				if ms.conflict(c) != ResolveTheirs {
+					continue
+				}
+			}
+			oObj.elem().FieldByName(f.Name()).Set(tObj.elem().FieldByName(f.Name()))
+			changed = true
+
+		case referenceField, dynamicField:
+			var bd skyobject.Dynamic
+			if bfv != nil {
+				if bd, e = decodeReference(ms.base, f, bfv); e != nil {
+					return nDyn, e
+				}
+			}
+			od, e := decodeReference(ms.ours, f, ofv)
+			if e != nil {
+				return nDyn, e
+			}
+			td, e := decodeReference(ms.theirs, f, tfv)
+			if e != nil {
+				return nDyn, e
+			}
+			nd, e := ms.mergeChild(path, f.Name(), -1, bd, od, td)
+			if e != nil {
+				return nDyn, e
+			}
+			if nd != od {
+				if e := oObj.setChild(f.Name(), -1, nd); e != nil {
+					return nDyn, e
+				}
+				changed = true
+			}
+
+		case referencesField:
+			var bds []skyobject.Dynamic
+			if bfv != nil {
+				if _, bds, e = decodeReferences(ms.base, f, bfv); e != nil {
+					return nDyn, e
+				}
+			}
+			_, ods, e := decodeReferences(ms.ours, f, ofv)
+			if e != nil {
+				return nDyn, e
+			}
+			_, tds, e := decodeReferences(ms.theirs, f, tfv)
+			if e != nil {
+				return nDyn, e
+			}
+			nds, e := ms.mergeList(path, f.Name(), bds, ods, tds, bfv, ofv, tfv)
+			if e != nil {
+				return nDyn, e
+			}
+			if dynamicsEqual(nds, ods) == false {
+				refs := make(skyobject.References, len(nds))
+				for i, dyn := range nds {
+					refs[i] = dyn.Object
+				}
+				if e := oObj.replaceReferencesField(f.Name(), refs); e != nil {
+					return nDyn, e
+				}
+				changed = true
+			}
+		}
+	}
+
+	// Save merged object.
+	if changed == false || ms.dryRun {
+		return o, nil
+	}
+	ref, e := ms.w.save(po, -1)
+	if e != nil {
+		return
+	}
+	nDyn = skyobject.Dynamic{Object: ref, Schema: o.Schema}
+	ms.w.displace(o)
+	return
+}
+
+// Helper function. Returns true if dynamic references 'a' and 'b' are equal, element-wise.
+func dynamicsEqual(a, b []skyobject.Dynamic) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Helper function. Returns true if 'dyns' contains 'dyn'.
+func containsDynamic(dyns []skyobject.Dynamic, dyn skyobject.Dynamic) bool {
+	for _, d := range dyns {
+		if d == dyn {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package skywalker
+
+import (
+	"github.com/skycoin/skycoin/src/cipher"
+	"testing"
+)
+
+func TestWalker_Merge(t *testing.T) {
+	client := newClient()
+	defer client.Close()
+	c := client.Container()
+
+	// Both versions of the root diverge from the same content.
+	basePK, baseSK := cipher.GenerateDeterministicKeyPair([]byte("base"))
+	oursPK, oursSK := cipher.GenerateDeterministicKeyPair([]byte("ours"))
+	theirsPK, theirsSK := cipher.GenerateDeterministicKeyPair([]byte("theirs"))
+	base := fillContainer1(c, basePK, baseSK)
+	ours, _ := NewRootWalker(fillContainer1(c, oursPK, oursSK), oursPK, oursSK, WithTypes(testTypes))
+	theirs, _ := NewRootWalker(fillContainer1(c, theirsPK, theirsSK), theirsPK, theirsSK, WithTypes(testTypes))
+
+	// We replace the creator of thread "Greetings", and the featured post of board "Test".
+	advanceToThread(t, ours, "Talk", "Greetings", &Board{}, &Thread{})
+	if _, e := ours.ReplaceInRefField("Creator", Person{"Bruce Lee", 77}); e != nil {
+		t.Fatal("failed to replace:", e)
+	}
+	advanceToBoard(t, ours, "Test", &Board{})
+	if _, e := ours.ReplaceInDynamicField("Featured", Post{Title: "Ours"}); e != nil {
+		t.Fatal("failed to replace:", e)
+	}
+
+	// They append a post to thread "Expressions", and replace the featured post of board "Test".
+	advanceToThread(t, theirs, "Talk", "Expressions", &Board{}, &Thread{})
+	if e := theirs.AppendToRefsField("Posts", Post{Title: "New", Body: "Merged?"}); e != nil {
+		t.Fatal("failed to append:", e)
+	}
+	advanceToBoard(t, theirs, "Test", &Board{})
+	if _, e := theirs.ReplaceInDynamicField("Featured", Post{Title: "Theirs"}); e != nil {
+		t.Fatal("failed to replace:", e)
+	}
+
+	// Only the titles of the featured posts conflict.
+	m, e := ours.Merge(base, theirs.r)
+	if e != nil {
+		t.Fatal("failed to merge:", e)
+	}
+	if len(m.Conflicts) != 1 {
+		t.Fatal("expected 1 conflict, got", len(m.Conflicts))
+	}
+	if s := m.Conflicts[0].String(); s != "Root.Refs[0] > Board.Featured > Post.Title" {
+		t.Error("unexpected conflict:", s)
+	}
+	if e := m.Commit(); e != ErrUnresolvedConflicts {
+		t.Error("expected error", ErrUnresolvedConflicts, "got", e)
+	}
+	m.Conflicts[0].Resolution = ResolveTheirs
+	if m.Resolved() == false {
+		t.Error("expected merge to be resolved")
+	}
+	if e := m.Commit(); e != nil {
+		t.Fatal("failed to commit merge:", e)
+	}
+
+	// Merged root has the changes of both sides.
+	board, thread := &Board{}, &Thread{}
+	advanceToThread(t, ours, "Talk", "Greetings", board, thread)
+	if e := ours.AdvanceFromRefField("Creator", &Person{}); e != nil {
+		t.Fatal("advance from thread to creator failed:", e)
+	}
+	if cur, _ := ours.Current(); cur.Value.(Person).Name != "Bruce Lee" {
+		t.Error("expected our creator, got", cur.Value)
+	}
+	advanceToThread(t, ours, "Talk", "Expressions", board, thread)
+	if len(thread.Posts) != 4 {
+		t.Error("expected their post to be appended, got", len(thread.Posts), "posts")
+	}
+	advanceToBoard(t, ours, "Test", board)
+	post := &Post{}
+	if e := ours.AdvanceFromDynamicField("Featured", post); e != nil {
+		t.Fatal("advance from board to featured post failed:", e)
+	}
+	if post.Title != "Theirs" {
+		t.Error("expected their featured post, got", post.Title)
+	}
+}
@@ -0,0 +1,110 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"reflect"
+)
+
+// MigrateFunc transforms object 'old' of an old Go type, into an object of the new Go type of the schema.
+// The returned object is saved in place of the old object.
+type MigrateFunc func(old interface{}) (interface{}, error)
+
+// Migrate migrates all objects of schema 'schemaName' in the root's tree to the new layout of the schema, as registered
+// in the registry of root 'dst' (a root of the same public key, created with the new registry). Each object is
+// deserialized into a pointer to the old Go type created with 'oldP', which is required to match the schema the object
+// is stored with, transformed with 'fn', and saved with the new schema.
+// The parents that reference the migrated objects, and all their ancestors, are re-saved with the new registry, and
+// the tags of their reference fields are required to resolve to the new schemas of their children. Objects of other
+// schemas are kept if their schemas are unchanged in the new registry, and are re-saved otherwise. The Go types of all
+// re-saved objects are required to be provided with WithTypes, and to be registered in the new registry. The Go types,
+// and the layout of the old Go type, are checked before anything is saved.
+// The references of 'dst' are replaced once, and the walker walks 'dst' afterwards. The Go type of the schema provided
+// with WithTypes is replaced with the Go type of the migrated objects.
+// Objects of the internal stack are restored along the same path afterwards. If an object of the internal stack is of
+// schema 'schemaName', or the path no longer exists, the internal stack is cleared and ErrStackCleared is returned
+// along with the report, as the root is migrated regardless.
+func (w *RootWalker) Migrate(dst *node.Root, schemaName string, oldP func() interface{}, fn MigrateFunc) (
+	report *RewriteReport, e error,
+) {
+	var restoreErr error
+	e = w.mutateRoot("Migrate", func(r *node.Root) (bool, error) {
+		// Check new root.
+		if dst == nil {
+			return false, ErrRootNotFound
+		}
+		if dst.Pub() != w.rpk {
+			return false, ErrRootKeyMismatch
+		}
+		if _, e := dst.SchemaByName(schemaName); e != nil {
+			return false, ErrSchemaMismatch
+		}
+
+		// Check functions.
+		if oldP == nil || fn == nil {
+			return false, ErrFuncNotProvided
+		}
+
+		rw := w.newRewriter(r)
+		rw.dst = dst
+		rw.schemaName = schemaName
+		rw.newP = oldP
+		var newType reflect.Type
+		rw.transform = func(p interface{}) (interface{}, bool, error) {
+			np, e := fn(p)
+			if e == nil {
+				newType = reflect.Indirect(reflect.ValueOf(np)).Type()
+			}
+			return np, true, e
+		}
+		report = rw.report
+		changed, e := rw.rewriteRoot()
+		if changed {
+			if _, has := w.types[schemaName]; has && newType != nil {
+				w.types[schemaName] = newType
+			}
+			restoreErr = w.restoreMigratedStack(schemaName)
+		}
+		return changed, e
+	})
+	if e != nil {
+		return nil, e
+	}
+	return report, restoreErr
+}
+
+// Helper function. Restores the internal stack after objects of schema 'schemaName' are migrated.
+// The internal stack is cleared if it has objects of the schema, as they are deserialized into old Go types.
+func (w *RootWalker) restoreMigratedStack(schemaName string) error {
+	for _, step := range w.Path() {
+		if step.Schema == schemaName {
+			w.Clear()
+			return ErrStackCleared
+		}
+	}
+	return w.restoreStack()
+}
+
+// Helper function. Checks whether Go type 't' (or the type it points to) has the layout of schema 's'; the same
+// exported fields, in the same order, of the same kinds.
+func checkLayout(t reflect.Type, s skyobject.Schema) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	fields := s.Fields()
+	n := 0
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		if ft.PkgPath != "" {
+			continue
+		}
+		if n >= len(fields) || fields[n].Name() != ft.Name || fields[n].Kind() != ft.Type.Kind() {
+			return false
+		}
+		n++
+	}
+	return n == len(fields)
+}
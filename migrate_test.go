@@ -0,0 +1,120 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/skyobject"
+	"github.com/skycoin/skycoin/src/cipher"
+	"testing"
+)
+
+// PostV2 is the new layout of Post, with a number of likes.
+type PostV2 struct {
+	Title  string
+	Body   string
+	Author skyobject.Reference `skyobject:"schema=Person"`
+	Likes  uint32
+}
+
+func TestWalker_Migrate(t *testing.T) {
+	client, w := newTestWalker(t, WithTypes(testTypes))
+	defer client.Close()
+	c, r := client.Container(), w.r
+	pk, sk := w.rpk, w.rsk
+
+	board := &Board{}
+	advanceToBoard(t, w, "Talk", board)
+	oldThreads := board.Threads
+
+	// The new registry registers the new layout of Post.
+	reg := skyobject.NewRegistry()
+	reg.Register("Person", Person{})
+	reg.Register("Post", PostV2{})
+	reg.Register("Thread", Thread{})
+	reg.Register("Board", Board{})
+	reg.Done()
+	c.AddRegistry(reg)
+	dst := c.NewRootReg(pk, sk, reg.Reference())
+
+	oldPost := func() interface{} { return &Post{} }
+	like := func(old interface{}) (interface{}, error) {
+		p := old.(*Post)
+		return PostV2{Title: p.Title, Body: p.Body, Author: p.Author, Likes: uint32(len(p.Body))}, nil
+	}
+
+	// Root of another public key is rejected.
+	otherPK, otherSK := cipher.GenerateDeterministicKeyPair([]byte("other"))
+	if _, e := w.Migrate(c.NewRootReg(otherPK, otherSK, reg.Reference()), "Post", oldPost, like); e != ErrRootKeyMismatch {
+		t.Error("expected error", ErrRootKeyMismatch, "got", e)
+	}
+
+	// Old Go type that does not match the stored schema is rejected.
+	newPost := func() interface{} { return &PostV2{} }
+	if _, e := w.Migrate(dst, "Post", newPost, like); e != ErrSchemaMismatch {
+		t.Error("expected error", ErrSchemaMismatch, "got", e)
+	}
+	if w.r == dst {
+		t.Fatal("failed migration moved the walker to the new root")
+	}
+
+	// Nothing is migrated without functions, or without the Go types of the re-saved objects.
+	if _, e := w.Migrate(dst, "Post", oldPost, nil); e != ErrFuncNotProvided {
+		t.Error("expected error", ErrFuncNotProvided, "got", e)
+	}
+	bare, _ := NewRootWalker(r, pk, sk)
+	if _, e := bare.Migrate(dst, "Post", oldPost, like); e != ErrTypeNotProvided {
+		t.Error("expected error", ErrTypeNotProvided, "got", e)
+	}
+	if bare.r == dst || len(bare.displaced) != 0 {
+		t.Fatal("failed migration changed the root")
+	}
+
+	// Every post is migrated, and liked by the length of it's body.
+	report, e := w.Migrate(dst, "Post", oldPost, like)
+	if e != nil {
+		t.Fatal("failed to migrate:", e)
+	}
+	t.Log(report)
+	if report.Changed != 8 {
+		t.Error("expected 8 posts to be migrated, got", report.Changed)
+	}
+	expected := map[string]int{"Person": 0, "Post": 8, "Thread": 3, "Board": 2}
+	for schemaName, n := range expected {
+		if report.Saved[schemaName] != n {
+			t.Errorf("expected %d re-saved objects of schema %s, got %d", n, schemaName, report.Saved[schemaName])
+		}
+	}
+	if w.r != dst {
+		t.Fatal("expected walker to walk the new root")
+	}
+
+	// The internal stack is restored with the new objects.
+	if w.Size() != 1 || board.Threads[0] == oldThreads[0] {
+		t.Fatal("expected internal stack to be restored")
+	}
+	if e := w.AdvanceFromRefsField("Threads", &Thread{}, findBy("Thread", "Name", "Greetings")); e != nil {
+		t.Fatal("advance from board to thread failed:", e)
+	}
+	post := &PostV2{}
+	if e := w.AdvanceFromRefsField("Posts", post, findBy("Post", "Title", "Hi")); e != nil {
+		t.Fatal("advance from thread to migrated post failed:", e)
+	}
+	if post.Body != "Hello?" || post.Likes != 6 {
+		t.Error("unexpected migrated post:", post)
+	}
+	v, _ := w.Value()
+	if schema, _ := dst.SchemaByName("Post"); v == nil || v.Schema().Reference() != schema.Reference() {
+		t.Error("expected migrated post to be saved with the new schema")
+	}
+
+	// Migrating posts again clears the internal stack, as it has a post.
+	same := func(old interface{}) (interface{}, error) { return old, nil }
+	report, e = w.Migrate(c.NewRootReg(pk, sk, reg.Reference()), "Post", newPost, same)
+	if e != ErrStackCleared {
+		t.Error("expected error", ErrStackCleared, "got", e)
+	}
+	if report == nil || report.Changed != 8 {
+		t.Error("expected report of 8 migrated posts, got", report)
+	}
+	if w.Size() != 0 {
+		t.Error("expected internal stack to be cleared")
+	}
+}
@@ -0,0 +1,137 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"github.com/skycoin/skycoin/src/cipher/encoder"
+	"reflect"
+	"time"
+)
+
+// Observer receives events of the walker's operations, for instrumentation and tracing.
+// Implementations shared between walkers should be safe for concurrent use.
+type Observer interface {
+	// ObserveLookup is called after an object's value is obtained from the root by dynamic reference.
+	ObserveLookup(dyn skyobject.Dynamic, d time.Duration, e error)
+
+	// ObserveDecode is called after an object (or a field of an object) of 'size' bytes is deserialized into a Go
+	// value. Reference fields decoded while traversing the root's tree are not observed.
+	ObserveDecode(size int, d time.Duration, e error)
+
+	// ObserveFinder is called after a finder is evaluated on an object of schema 'schemaName'.
+	ObserveFinder(schemaName string, chosen bool, d time.Duration)
+
+	// ObserveSave is called after an object is saved in the container. 'depth' is the depth of the object in the
+	// internal stack, or -1 if the object is not part of the internal stack.
+	ObserveSave(ref skyobject.Reference, depth int, d time.Duration)
+
+	// ObserveReplace is called after the references of the root are replaced.
+	ObserveReplace(d time.Duration)
+}
+
+// Helper function. Obtains the value of 'dyn' from the cache, or otherwise from root 'r', notifying the observer.
+func (w *RootWalker) valueByDynamic(r *node.Root, dyn skyobject.Dynamic) (v *skyobject.Value, e error) {
+	if w.cache != nil {
+		if v, has := w.cache.get(dyn); has {
+			return v, nil
+		}
+	}
+	if w.obs == nil {
+		v, e = r.ValueByDynamic(dyn)
+	} else {
+		start := time.Now()
+		v, e = r.ValueByDynamic(dyn)
+		w.obs.ObserveLookup(dyn, time.Since(start), e)
+	}
+	if w.cache != nil && e == nil {
+		w.cache.add(dyn, v)
+	}
+	return
+}
+
+// Helper function. Deserializes 'data' into the Go value 'p' points to, notifying the observer.
+func (w *RootWalker) deserialize(data []byte, p interface{}) error {
+	if w.obs == nil {
+		return encoder.DeserializeRaw(data, p)
+	}
+	start := time.Now()
+	e := encoder.DeserializeRaw(data, p)
+	w.obs.ObserveDecode(len(data), time.Since(start), e)
+	return e
+}
+
+// Helper function. Evaluates 'finder' on value 'v', notifying the observer.
+func (w *RootWalker) find(finder func(v *skyobject.Value) bool, v *skyobject.Value) bool {
+	if w.obs == nil {
+		return finder(v)
+	}
+	start := time.Now()
+	chosen := finder(v)
+	w.obs.ObserveFinder(v.Schema().Name(), chosen, time.Since(start))
+	return chosen
+}
+
+// Helper function. Validates and saves object 'p' in the container, notifying the observer.
+// 'depth' is the depth of the object in the internal stack, or -1 if the object is not part of the internal stack.
+func (w *RootWalker) save(p interface{}, depth int) (skyobject.Reference, error) {
+	if e := w.validate(w.r, p); e != nil {
+		return skyobject.Reference{}, e
+	}
+	if w.obs == nil {
+		return w.r.Save(p), nil
+	}
+	start := time.Now()
+	ref := w.r.Save(p)
+	w.obs.ObserveSave(ref, depth, time.Since(start))
+	return ref, nil
+}
+
+// Helper function. Validates and saves object 'p' in the container and obtains it's dynamic reference, notifying the
+// observer.
+func (w *RootWalker) saveDynamic(p interface{}, depth int) (skyobject.Dynamic, error) {
+	return w.saveDynamicTo(w.r, p, depth)
+}
+
+// Helper function. Behaves as saveDynamic, but obtains the dynamic reference with the registry of root 'r'.
+func (w *RootWalker) saveDynamicTo(r *node.Root, p interface{}, depth int) (skyobject.Dynamic, error) {
+	if e := w.validate(r, p); e != nil {
+		return skyobject.Dynamic{}, e
+	}
+	if w.obs == nil {
+		return r.Dynamic(p), nil
+	}
+	start := time.Now()
+	dyn := r.Dynamic(p)
+	w.obs.ObserveSave(dyn.Object, depth, time.Since(start))
+	return dyn, nil
+}
+
+// Helper function. Replaces the references of the root, notifying the observer.
+func (w *RootWalker) replace(rDyns []skyobject.Dynamic) {
+	if w.obs == nil {
+		w.r.Replace(rDyns)
+		return
+	}
+	start := time.Now()
+	w.r.Replace(rDyns)
+	w.obs.ObserveReplace(time.Since(start))
+}
+
+// Helper function. Decodes the object of 'dyn' with value 'v' into the Go value 'p' points to. If the walker has a
+// cache, the object is copied from the cache when it was decoded into the same Go type before, and is otherwise
+// deserialized and cached.
+func (w *RootWalker) decode(dyn skyobject.Dynamic, v *skyobject.Value, p interface{}) error {
+	pv := reflect.ValueOf(p)
+	if w.cache == nil || pv.Kind() != reflect.Ptr || pv.IsNil() {
+		return w.deserialize(v.Data(), p)
+	}
+	if cv, has := w.cache.getDecoded(dyn, pv.Elem().Type()); has {
+		pv.Elem().Set(deepCopy(cv))
+		return nil
+	}
+	if e := w.deserialize(v.Data(), p); e != nil {
+		return e
+	}
+	w.cache.addDecoded(dyn, deepCopy(pv.Elem()))
+	return nil
+}
@@ -0,0 +1,59 @@
+package skywalker
+
+import "reflect"
+
+// Option represents an optional setting of RootWalker, provided on creation.
+type Option func(w *RootWalker)
+
+// AutoPublish makes the walker publish the root after every successful mutation that changes the root.
+// Mutations of a walker created without a secret key still return ErrReadOnlyWalker, before anything is changed.
+func AutoPublish() Option {
+	return func(w *RootWalker) {
+		w.autoPublish = true
+	}
+}
+
+// VerifyRootSignature makes the walker verify that the root is signed by the root's public key before the first
+// advance, and again after every Refresh. Advancing from a root with a bad signature returns ErrBadRootSignature.
+func VerifyRootSignature() Option {
+	return func(w *RootWalker) {
+		w.verifySig = true
+	}
+}
+
+// WithTypes provides the Go types of the root's schemas, keyed by schema name, in the same way they are registered in
+// the root's registry. E.g. `WithTypes(map[string]interface{}{"Person": Person{}})`.
+// Actions that create or re-save objects without being provided with a destination pointer require these types.
+func WithTypes(types map[string]interface{}) Option {
+	return func(w *RootWalker) {
+		if w.types == nil {
+			w.types = make(map[string]reflect.Type)
+		}
+		for name, i := range types {
+			w.types[name] = reflect.Indirect(reflect.ValueOf(i)).Type()
+		}
+	}
+}
+
+// WithObserver makes the walker notify observer 'obs' of it's operations.
+func WithObserver(obs Observer) Option {
+	return func(w *RootWalker) {
+		w.obs = obs
+	}
+}
+
+// WithCache makes the walker cache object values obtained from the root, and the objects it decodes when advancing,
+// in 'c', which may be shared between walkers.
+func WithCache(c *Cache) Option {
+	return func(w *RootWalker) {
+		w.cache = c
+	}
+}
+
+// WithValidators makes the walker run the validators of registry 'vs' on every object before it is saved, including
+// the ancestors that are re-saved by mutations. A failed validation aborts the mutation with a *ValidationError.
+func WithValidators(vs *Validators) Option {
+	return func(w *RootWalker) {
+		w.validators = vs
+	}
+}
@@ -0,0 +1,104 @@
+package skywalker
+
+import (
+	"fmt"
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+)
+
+// PathStep represents a single step of a path; from an object (or the root) to one of it's children.
+type PathStep struct {
+	FieldName string // Field name of previous object used to find current. Empty if previous is the root.
+	Index     int    // Index in previous object's field. -1 if single reference (not array).
+	Schema    string // Schema name of current object.
+}
+
+// Path represents the location of an object in a root's tree, as steps taken from the root.
+type Path []PathStep
+
+// String creates a compact one-line representation of the path.
+// E.g. `Root.Refs[1] > Board.Threads[0] > Thread.Posts[2]`.
+func (p Path) String() (out string) {
+	prevName := "Root"
+	for i, step := range p {
+		if i > 0 {
+			out += " > "
+		}
+		fieldName := step.FieldName
+		if fieldName == "" {
+			fieldName = "Refs"
+		}
+		out += fmt.Sprintf("%s.%s", prevName, fieldName)
+		if step.Index != -1 {
+			out += fmt.Sprintf("[%d]", step.Index)
+		}
+		prevName = step.Schema
+	}
+	return
+}
+
+// Helper function. Creates a new path that extends the path with a step, without modifying the original.
+func (p Path) extend(fieldName string, index int, schemaName string) Path {
+	out := make(Path, len(p), len(p)+1)
+	copy(out, p)
+	return append(out, PathStep{FieldName: fieldName, Index: index, Schema: schemaName})
+}
+
+// Path returns the path of the top-most object of the internal stack.
+func (w *RootWalker) Path() (path Path) {
+	for _, obj := range w.stack {
+		schName := ""
+		s, _ := w.r.SchemaByReference(obj.s)
+		if s != nil {
+			schName = s.Name()
+		}
+		path = path.extend(obj.prevFieldName, obj.prevInFieldIndex, schName)
+	}
+	return
+}
+
+// Helper function. Resolves 'path' in root 'r' to the dynamic reference and value of the object at the path, without
+// the need of Go types. Returns ErrObjNotFound if the objects along the path no longer match the path.
+func (w *RootWalker) resolvePath(r *node.Root, path Path) (dyn skyobject.Dynamic, v *skyobject.Value, e error) {
+	if len(path) == 0 {
+		e = ErrObjNotFound
+		return
+	}
+	for i, step := range path {
+		// Obtain dynamic reference of child.
+		found := false
+		if i == 0 {
+			rDyns := r.Refs()
+			if step.Index >= 0 && step.Index < len(rDyns) {
+				dyn, found = rDyns[step.Index], true
+			}
+		} else {
+			for _, f := range v.Schema().Fields() {
+				if f.Name() != step.FieldName {
+					continue
+				}
+				children, e := fieldChildRefs(r, v, f)
+				if e != nil {
+					return dyn, nil, e
+				}
+				for _, c := range children {
+					if c.index == step.Index {
+						dyn, found = c.dyn, true
+					}
+				}
+			}
+		}
+		if found == false {
+			return dyn, nil, ErrObjNotFound
+		}
+
+		// Obtain value from root.
+		if v, e = w.valueByDynamic(r, dyn); e != nil {
+			return
+		}
+		if step.Schema != "" && v.Schema().Name() != step.Schema {
+			return dyn, nil, ErrObjNotFound
+		}
+	}
+	return
+}
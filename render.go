@@ -0,0 +1,229 @@
+package skywalker
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"strings"
+)
+
+// ANSI escape codes used for colored output.
+const (
+	colorReset  = "\x1b[0m"
+	colorSchema = "\x1b[36m" // Cyan.
+	colorField  = "\x1b[33m" // Yellow.
+	colorString = "\x1b[32m" // Green.
+	colorRef    = "\x1b[35m" // Magenta.
+)
+
+// RenderOptions represents the options for Render.
+type RenderOptions struct {
+	// Depth is the number of levels of referenced objects to inline. 0 inlines none, and negative inlines all.
+	Depth int
+
+	// HexRefs shows the hex of references, after the schema names of referenced objects.
+	HexRefs bool
+
+	// ResolveDynamic shows the schema names of objects referenced by dynamic references, instead of "Dynamic".
+	ResolveDynamic bool
+
+	// Color colors the output with ANSI escape codes, for terminals.
+	Color bool
+
+	// Compact renders the path of the internal stack on one line.
+	// E.g. `Root.Refs[1] > Board.Threads[0] > Thread.Posts[2]`.
+	Compact bool
+}
+
+// Render creates a readable string that shows the objects of the internal stack with their field names, as an
+// alternative to String. Referenced objects are inlined as given by 'opts'.
+func (w *RootWalker) Render(opts RenderOptions) (string, error) {
+	// Check root.
+	r, e := w.root()
+	if e != nil {
+		return "", e
+	}
+	if opts.Compact {
+		return w.Path().String(), nil
+	}
+
+	rd := &renderer{w: w, r: r, opts: opts, buf: new(bytes.Buffer)}
+	rd.buf.WriteString("Root")
+	for i, obj := range w.stack {
+		// Render step to object.
+		fieldName := "Refs"
+		if i > 0 {
+			fieldName = obj.prevFieldName
+			rd.indent(i)
+			rd.buf.WriteString("  " + rd.color(colorSchema, rd.schemaName(obj.prev.s)))
+		}
+		rd.buf.WriteString("." + rd.color(colorField, fieldName))
+		if obj.prevInFieldIndex != -1 {
+			rd.buf.WriteString(fmt.Sprintf("[%d]", obj.prevInFieldIndex))
+		}
+		rd.buf.WriteString(" ->\n")
+
+		// Render object.
+		dyn, e := obj.dynamic()
+		if e != nil {
+			return "", e
+		}
+		v, e := w.valueByDynamic(r, dyn)
+		if e != nil {
+			return "", e
+		}
+		rd.indent(i)
+		rd.buf.WriteString("  ")
+		if e := rd.object(dyn, v, i, opts.Depth); e != nil {
+			return "", e
+		}
+		rd.buf.WriteString("\n")
+	}
+	return rd.buf.String(), nil
+}
+
+// renderer represents a readable string being rendered.
+type renderer struct {
+	w    *RootWalker
+	r    *node.Root
+	opts RenderOptions
+	buf  *bytes.Buffer
+}
+
+// Helper function. Writes 'n' tabs.
+func (rd *renderer) indent(n int) {
+	rd.buf.WriteString(strings.Repeat("\t", n))
+}
+
+// Helper function. Colors string 's' if colored output is enabled.
+func (rd *renderer) color(color, s string) string {
+	if rd.opts.Color == false || s == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// Helper function. Obtains the name of schema 'sr'.
+func (rd *renderer) schemaName(sr skyobject.SchemaReference) string {
+	if s, _ := rd.r.SchemaByReference(sr); s != nil {
+		return s.Name()
+	}
+	return ""
+}
+
+// Helper function. Renders the object of 'dyn' with value 'v' over multiple lines, indented by 'level' tabs and
+// inlining 'depth' levels of referenced objects.
+func (rd *renderer) object(dyn skyobject.Dynamic, v *skyobject.Value, level, depth int) error {
+	rd.buf.WriteString(rd.color(colorSchema, v.Schema().Name()))
+	if rd.opts.HexRefs {
+		rd.buf.WriteString(rd.color(colorRef, "#"+refHex(dyn.Object)))
+	}
+	rd.buf.WriteString(" {\n")
+
+	for _, f := range v.Schema().Fields() {
+		fv, e := v.FieldByName(f.Name())
+		if e != nil {
+			return e
+		}
+		rd.indent(level + 1)
+		rd.buf.WriteString("  " + rd.color(colorField, f.Name()) + ": ")
+
+		switch kindOfField(f) {
+		case referencesField:
+			_, dyns, e := decodeReferences(rd.r, f, fv)
+			if e != nil {
+				return e
+			}
+			rd.buf.WriteString("[")
+			for i, dyn := range dyns {
+				if depth != 0 {
+					rd.buf.WriteString("\n")
+					rd.indent(level + 2)
+					rd.buf.WriteString("  ")
+				} else if i > 0 {
+					rd.buf.WriteString(", ")
+				}
+				if e := rd.ref(f, dyn, level+2, depth); e != nil {
+					return e
+				}
+			}
+			if depth != 0 && len(dyns) > 0 {
+				rd.buf.WriteString("\n")
+				rd.indent(level + 1)
+				rd.buf.WriteString("  ")
+			}
+			rd.buf.WriteString("]")
+		case referenceField, dynamicField:
+			dyn, e := decodeReference(rd.r, f, fv)
+			if e != nil {
+				return e
+			}
+			if e := rd.ref(f, dyn, level+1, depth); e != nil {
+				return e
+			}
+		default:
+			pv, e := rd.w.plainValue(fv)
+			if e != nil {
+				return e
+			}
+			rd.buf.WriteString(rd.plain(pv))
+		}
+		rd.buf.WriteString("\n")
+	}
+
+	rd.indent(level)
+	rd.buf.WriteString("  }")
+	return nil
+}
+
+// Helper function. Renders a reference held by field 'f'; as an inlined object if 'depth' allows, otherwise as the
+// schema name of the referenced object.
+func (rd *renderer) ref(f skyobject.Field, dyn skyobject.Dynamic, level, depth int) error {
+	if dyn.Object == (skyobject.Reference{}) {
+		rd.buf.WriteString("nil")
+		return nil
+	}
+	if depth != 0 {
+		v, e := rd.w.valueByDynamic(rd.r, dyn)
+		if e != nil {
+			return e
+		}
+		return rd.object(dyn, v, level, depth-1)
+	}
+
+	schemaName := schemaNameFromTag(f.Tag())
+	if kindOfField(f) == dynamicField {
+		schemaName = dynamicTypeName
+		if rd.opts.ResolveDynamic {
+			schemaName = fmt.Sprintf("%s(%s)", dynamicTypeName, rd.schemaName(dyn.Schema))
+		}
+	}
+	rd.buf.WriteString("&" + rd.color(colorSchema, schemaName))
+	if rd.opts.HexRefs {
+		rd.buf.WriteString(rd.color(colorRef, "#"+refHex(dyn.Object)))
+	}
+	return nil
+}
+
+// Helper function. Renders a plain value, as obtained with plainValue, on a single line.
+func (rd *renderer) plain(pv interface{}) string {
+	switch x := pv.(type) {
+	case string:
+		return rd.color(colorString, fmt.Sprintf("%q", x))
+	case []interface{}:
+		items := make([]string, len(x))
+		for i, item := range x {
+			items[i] = rd.plain(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case jsonObject:
+		fields := make([]string, len(x))
+		for i, f := range x {
+			fields[i] = rd.color(colorField, f.name) + ": " + rd.plain(f.value)
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return fmt.Sprint(x)
+	}
+}
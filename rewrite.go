@@ -0,0 +1,333 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"reflect"
+)
+
+// rewriter rewrites the tree of a root bottom-up. Objects are rewritten after their children, and only objects that
+// change (or that have children that change) are re-saved. As objects are content-addressed, each object is rewritten
+// once, no matter how many parents reference it.
+type rewriter struct {
+	w   *RootWalker
+	r   *node.Root
+	dst *node.Root // Root the rewritten objects are saved to. Differs from 'r' when migrating to a new registry.
+
+	// Replaces the objects that 'replaces' reports as a whole, in which case their children are not rewritten.
+	replaces func(dyn skyobject.Dynamic) bool
+	replace  func(dyn skyobject.Dynamic) (nDyn skyobject.Dynamic, e error)
+
+	// Transforms the objects of schema 'schemaName' after their children are rewritten. The objects are deserialized
+	// into a pointer created with 'newP', and 'transform' obtains the object to save in their place.
+	schemaName string
+	newP       func() interface{}
+	transform  func(p interface{}) (np interface{}, changed bool, e error)
+
+	done   map[skyobject.Reference]skyobject.Dynamic // New dynamic references of rewritten objects.
+	report *RewriteReport
+}
+
+// RewriteReport reports the objects re-saved by a rewrite of the whole root.
+type RewriteReport struct {
+	Changed int            // Number of objects of the rewritten schema that are changed.
+	Saved   map[string]int // Number of re-saved objects, keyed by schema name. Includes re-saved ancestors.
+}
+
+func (w *RootWalker) newRewriter(r *node.Root) *rewriter {
+	return &rewriter{
+		w:      w,
+		r:      r,
+		dst:    r,
+		done:   make(map[skyobject.Reference]skyobject.Dynamic),
+		report: &RewriteReport{Saved: make(map[string]int)},
+	}
+}
+
+// Helper function. Rewrites the whole tree of the root, and replaces the root's references once if anything changed.
+// When migrating, the walker is moved to the root the objects are saved to, which is always changed.
+// The whole tree is checked before anything is saved.
+func (rw *rewriter) rewriteRoot() (changed bool, e error) {
+	checked := make(map[skyobject.Reference]bool)
+	for _, c := range rootChildRefs(rw.r) {
+		if _, e := rw.check(c.dyn, checked); e != nil {
+			return false, e
+		}
+	}
+	rDyns := rw.r.Refs()
+	for _, c := range rootChildRefs(rw.r) {
+		nDyn, e := rw.rewrite(c.dyn)
+		if e != nil {
+			return false, e
+		}
+		if nDyn != c.dyn {
+			rDyns[c.index] = nDyn
+			changed = true
+		}
+	}
+	if rw.migrating() {
+		rw.w.r = rw.dst
+		changed = true
+	}
+	if changed {
+		rw.w.replace(rDyns)
+	}
+	return
+}
+
+// Helper function. Checks whether the rewritten objects are saved to another root.
+func (rw *rewriter) migrating() bool {
+	return rw.dst != rw.r
+}
+
+// Helper function. Rewrites the object of 'dyn' and it's descendants, and obtains the object's new dynamic reference.
+func (rw *rewriter) rewrite(dyn skyobject.Dynamic) (nDyn skyobject.Dynamic, e error) {
+	if nDyn, has := rw.done[dyn.Object]; has {
+		return nDyn, nil
+	}
+
+	// Replace object as a whole.
+	if rw.replaces != nil && rw.replaces(dyn) {
+		if nDyn, e = rw.replace(dyn); e != nil {
+			return
+		}
+		rw.done[dyn.Object] = nDyn
+		return
+	}
+
+	// Obtain value from root.
+	v, e := rw.w.valueByDynamic(rw.r, dyn)
+	if e != nil {
+		return
+	}
+
+	// Rewrite children.
+	children, e := childRefs(rw.r, v)
+	if e != nil {
+		return
+	}
+	var changes []childRef
+	for _, c := range children {
+		nc, e := rw.rewrite(c.dyn)
+		if e != nil {
+			return nDyn, e
+		}
+		if rw.migrating() {
+			if e := rw.checkTag(v, c.fieldName, nc); e != nil {
+				return nDyn, e
+			}
+		}
+		if nc != c.dyn {
+			changes = append(changes, childRef{fieldName: c.fieldName, index: c.index, dyn: nc})
+		}
+	}
+	schemaName := v.Schema().Name()
+	matched := rw.transform != nil && schemaName == rw.schemaName
+
+	// When migrating, objects are re-saved if their schema differs in the registry of the new root.
+	dstSchema := dyn.Schema
+	if rw.migrating() {
+		s, e := rw.dst.SchemaByName(schemaName)
+		if e != nil {
+			return nDyn, ErrSchemaMismatch
+		}
+		dstSchema = s.Reference()
+	}
+	moved := dstSchema != dyn.Schema
+	if len(changes) == 0 && matched == false && moved == false {
+		rw.done[dyn.Object] = dyn
+		return dyn, nil
+	}
+
+	// Decode object and apply changes of children.
+	var p interface{}
+	if matched {
+		p = rw.newP()
+	} else if p, e = rw.w.newObjOfSchema(schemaName); e != nil {
+		return
+	}
+	if e = rw.w.deserialize(v.Data(), p); e != nil {
+		return
+	}
+	obj := &wrappedObj{s: dyn.Schema, p: p, v: v, w: rw.w}
+	for _, c := range changes {
+		if e = obj.setChild(c.fieldName, c.index, c.dyn); e != nil {
+			return
+		}
+	}
+
+	// Transform object.
+	changed := len(changes) > 0 || moved
+	if matched {
+		var tChanged bool
+		if p, tChanged, e = rw.transform(p); e != nil {
+			return
+		}
+		changed = changed || tChanged
+	}
+
+	// Re-save object if changed.
+	nDyn = dyn
+	if changed {
+		if nDyn, e = rw.w.saveDynamicTo(rw.dst, p, -1); e != nil {
+			return
+		}
+		if rw.migrating() && nDyn.Schema != dstSchema {
+			return nDyn, ErrSchemaMismatch
+		}
+	}
+	if nDyn == dyn {
+		rw.done[dyn.Object] = dyn
+		return
+	}
+	if matched {
+		rw.report.Changed++
+	}
+	rw.report.Saved[schemaName]++
+	rw.w.displace(dyn)
+	rw.done[dyn.Object] = nDyn
+	return
+}
+
+// Helper function. Checks that the object of 'dyn' and it's descendants can be rewritten, before anything is saved.
+// The Go types of all objects that may be re-saved are required to be provided with WithTypes. When migrating, the
+// schemas of all objects are also required to resolve in the new root, and 'newP' to match the stored layout.
+// Obtains whether the object may be re-saved (or replaced). 'checked' holds the results of the objects checked so far.
+func (rw *rewriter) check(dyn skyobject.Dynamic, checked map[skyobject.Reference]bool) (affected bool, e error) {
+	if affected, has := checked[dyn.Object]; has {
+		return affected, nil
+	}
+	if rw.replaces != nil && rw.replaces(dyn) {
+		checked[dyn.Object] = true
+		return true, nil
+	}
+
+	// Check children.
+	v, e := rw.w.valueByDynamic(rw.r, dyn)
+	if e != nil {
+		return
+	}
+	children, e := childRefs(rw.r, v)
+	if e != nil {
+		return
+	}
+	for _, c := range children {
+		cAffected, e := rw.check(c.dyn, checked)
+		if e != nil {
+			return false, e
+		}
+		affected = affected || cAffected
+	}
+
+	// When migrating, objects are re-saved if their schema differs in the registry of the new root.
+	schemaName := v.Schema().Name()
+	moved := false
+	if rw.migrating() {
+		s, e := rw.dst.SchemaByName(schemaName)
+		if e != nil {
+			return false, ErrSchemaMismatch
+		}
+		moved = s.Reference() != dyn.Schema
+	}
+
+	// Transformed objects are re-saved with the Go type of 'newP', and others with their Go types.
+	if rw.transform != nil && schemaName == rw.schemaName {
+		if rw.migrating() && checkLayout(reflect.TypeOf(rw.newP()), v.Schema()) == false {
+			return false, ErrSchemaMismatch
+		}
+		affected = true
+	} else if affected || moved {
+		if _, has := rw.w.types[schemaName]; has == false {
+			return false, ErrTypeNotProvided
+		}
+		affected = true
+	}
+	checked[dyn.Object] = affected
+	return
+}
+
+// Helper function. Checks that the tag of field 'fieldName' of object value 'v' resolves to the schema of the field's
+// rewritten child 'nc' in the registry of the root the objects are saved to. Dynamic fields have no tag to check.
+func (rw *rewriter) checkTag(v *skyobject.Value, fieldName string, nc skyobject.Dynamic) error {
+	for _, f := range v.Schema().Fields() {
+		if f.Name() != fieldName {
+			continue
+		}
+		if kindOfField(f) == dynamicField {
+			return nil
+		}
+		s, e := rw.dst.SchemaByName(schemaNameFromTag(f.Tag()))
+		if e != nil || s.Reference() != nc.Schema {
+			return ErrSchemaMismatch
+		}
+		return nil
+	}
+	return ErrFieldNotFound
+}
+
+// Helper function. Restores the internal stack along it's path after the root is rewritten, so that the objects of
+// the internal stack are up to date. The objects are deserialized into the same pointers, and objects that were
+// advanced to lazily stay lazy. If the path no longer exists, the internal stack is cleared and ErrStackCleared is
+// returned.
+func (w *RootWalker) restoreStack() error {
+	path := w.Path()
+	ps := make([]interface{}, len(w.stack))
+	for i, obj := range w.stack {
+		ps[i] = obj.p
+	}
+	if e := w.Restore(path, ps...); e != nil {
+		return ErrStackCleared
+	}
+	return nil
+}
+
+// ReplaceEverywhere replaces the object of reference 'oldRef' with the object 'p' points to, in all the parents that
+// reference it across the root's tree, re-saving all their ancestors. This is unlike ReplaceInRefField and
+// ReplaceInDynamicField, which only replace the object along the path of the internal stack. As objects are
+// content-addressed, identical objects (e.g. the same person) share a reference, and are all replaced.
+// The root is changed once, and the Go types of all re-saved ancestors are required to be provided with WithTypes;
+// they are checked before anything is saved, and ErrTypeNotProvided is returned otherwise.
+// The paths of the replaced object, as they were before the replacement, are returned. The internal stack is restored
+// along the same path afterwards; if the path no longer exists (e.g. a replaced object has fewer references), the
+// internal stack is cleared and ErrStackCleared is returned along with the paths.
+func (w *RootWalker) ReplaceEverywhere(oldRef skyobject.Reference, p interface{}) (paths []Path, e error) {
+	var restoreErr error
+	e = w.mutateRoot("ReplaceEverywhere", func(r *node.Root) (bool, error) {
+		// Obtain paths of the object.
+		x, e := newReverseIndex(r, func(dyn skyobject.Dynamic) (*skyobject.Value, error) {
+			return w.valueByDynamic(r, dyn)
+		})
+		if e != nil {
+			return false, e
+		}
+		if x.Has(oldRef) == false {
+			return false, ErrObjNotFound
+		}
+		paths = x.Paths(oldRef)
+
+		// Rewrite all parents, saving the new object once they are checked.
+		rw := w.newRewriter(r)
+		rw.replaces = func(dyn skyobject.Dynamic) bool {
+			return dyn.Object == oldRef
+		}
+		rw.replace = func(dyn skyobject.Dynamic) (skyobject.Dynamic, error) {
+			nDyn, e := w.saveDynamic(p, -1)
+			if e != nil {
+				return dyn, e
+			}
+			if nDyn.Object != oldRef {
+				w.displace(dyn)
+			}
+			return nDyn, nil
+		}
+		changed, e := rw.rewriteRoot()
+		if changed {
+			restoreErr = w.restoreStack()
+		}
+		return changed, e
+	})
+	if e == nil {
+		e = restoreErr
+	}
+	return
+}
@@ -0,0 +1,81 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/skyobject"
+	"testing"
+)
+
+func TestWalker_ReplaceEverywhere(t *testing.T) {
+	client, w := newTestWalker(t, WithTypes(testTypes))
+	defer client.Close()
+	r := w.r
+
+	board := &Board{}
+	advanceToBoard(t, w, "Talk", board)
+	oldThreads := append(skyobject.References{}, board.Threads...)
+
+	// Luis is the creator of board "Test" and thread "Testing", and the author of two posts of board "Talk".
+	luis := r.Save(Person{"Luis", 16})
+
+	// Nothing is replaced without the Go types of the ancestors.
+	bare, _ := NewRootWalker(r, w.rpk, w.rsk)
+	if _, e := bare.ReplaceEverywhere(luis, Person{"Luis", 17}); e != ErrTypeNotProvided {
+		t.Error("expected error", ErrTypeNotProvided, "got", e)
+	}
+	if len(bare.displaced) != 0 {
+		t.Error("expected nothing to be replaced")
+	}
+	paths, e := w.ReplaceEverywhere(luis, Person{"Luis", 17})
+	if e != nil {
+		t.Fatal("failed to replace everywhere:", e)
+	}
+	for _, path := range paths {
+		t.Log(path)
+	}
+	if len(paths) != 4 {
+		t.Error("expected 4 paths, got", len(paths))
+	}
+
+	x, e := w.ReverseIndex()
+	if e != nil {
+		t.Fatal("failed to build reverse index:", e)
+	}
+	if x.Has(luis) {
+		t.Error("expected old Luis to be replaced")
+	}
+	if n := len(x.Parents(r.Save(Person{"Luis", 17}))); n != 4 {
+		t.Error("expected 4 parents of new Luis, got", n)
+	}
+
+	// The internal stack is restored with the new objects.
+	if w.Size() != 1 || board.Name != "Talk" {
+		t.Fatal("expected internal stack to be restored")
+	}
+	if board.Threads[0] == oldThreads[0] || board.Threads[1] == oldThreads[1] {
+		t.Error("expected threads of board to be re-saved")
+	}
+
+	// Replacing thread "Greetings" with one of a single post removes the path to post "Howdy".
+	thread := &Thread{}
+	if e := w.AdvanceFromRefsField("Threads", thread, findBy("Thread", "Name", "Greetings")); e != nil {
+		t.Fatal("advance from board to thread failed:", e)
+	}
+	if e := w.AdvanceFromRefsField("Posts", &Post{}, findBy("Post", "Title", "Howdy")); e != nil {
+		t.Fatal("advance from thread to post failed:", e)
+	}
+	threadRef := board.Threads[0]
+	short := Thread{thread.Name, thread.Creator, thread.Posts[:1]}
+	if _, e := w.ReplaceEverywhere(threadRef, short); e != ErrStackCleared {
+		t.Error("expected error", ErrStackCleared, "got", e)
+	}
+	if w.Size() != 0 {
+		t.Error("expected internal stack to be cleared")
+	}
+	if x, _ := w.ReverseIndex(); x == nil || x.Has(threadRef) {
+		t.Error("expected thread to be replaced regardless")
+	}
+
+	if _, e := w.ReplaceEverywhere(luis, Person{"Luis", 18}); e != ErrObjNotFound {
+		t.Error("expected error", ErrObjNotFound, "got", e)
+	}
+}
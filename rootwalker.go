@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"github.com/skycoin/cxo/skyobject"
 	"github.com/skycoin/skycoin/src/cipher"
-	"github.com/skycoin/skycoin/src/cipher/encoder"
 	"github.com/skycoin/cxo/node"
+	"reflect"
 )
 
 // RootWalker represents an object the walks a root's tree.
@@ -15,10 +15,25 @@ type RootWalker struct {
 	rsk   cipher.SecKey
 	r     *node.Root
 	stack []*wrappedObj
+
+	obs         Observer
+	cache       *Cache
+	autoPublish bool
+	verifySig   bool
+	verified    bool
+
+	displaced     []skyobject.Dynamic              // Objects replaced by mutations, which may no longer be reachable.
+	displacedRefs map[skyobject.Reference]struct{} // References of 'displaced', so that objects are recorded once.
+	types         map[string]reflect.Type
+
+	beforeHooks []BeforeMutateHook
+	afterHooks  []AfterMutateHook
+	validators  *Validators
 }
 
 // NewRootWalker creates a new walker with given container and root's public key.
-func NewRootWalker(r *node.Root, rpk cipher.PubKey, rsk cipher.SecKey) (w *RootWalker, e error) {
+// If an empty secret key is provided, the walker is read-only.
+func NewRootWalker(r *node.Root, rpk cipher.PubKey, rsk cipher.SecKey, opts ...Option) (w *RootWalker, e error) {
 	if r == nil {
 		e = errors.New("nil container error")
 		return
@@ -28,9 +43,57 @@ func NewRootWalker(r *node.Root, rpk cipher.PubKey, rsk cipher.SecKey) (w *RootW
 		rsk: rsk,
 		r:   r,
 	}
+	for _, opt := range opts {
+		opt(w)
+	}
 	return
 }
 
+// NewReadOnlyRootWalker creates a new walker of root 'r' that only requires the root's public key.
+// The walker can advance and retreat, but all mutating methods return ErrReadOnlyWalker.
+func NewReadOnlyRootWalker(r *node.Root, rpk cipher.PubKey, opts ...Option) (w *RootWalker, e error) {
+	return NewRootWalker(r, rpk, cipher.SecKey{}, opts...)
+}
+
+// NewRootWalkerFromContainer creates a new walker of the latest root of public key 'pk' in container 'c'.
+// If secret key 'sk' is provided, the root is obtained with the secret key attached so that it can be signed, and an
+// empty root is created if no such root exists. Otherwise, the latest root that has all it's objects is obtained, and
+// ErrRootNotFound is returned if there is none. ErrKeyPairMismatch is returned if 'sk' does not belong to 'pk'.
+func NewRootWalkerFromContainer(c *node.Container, pk cipher.PubKey, sk cipher.SecKey, opts ...Option) (
+	w *RootWalker, e error,
+) {
+	if c == nil {
+		e = errors.New("nil container error")
+		return
+	}
+
+	// Read-only walker of a full root.
+	if sk == (cipher.SecKey{}) {
+		r := c.LastFullRoot(pk)
+		if r == nil {
+			e = ErrRootNotFound
+			return
+		}
+		return NewRootWalker(r, pk, sk, opts...)
+	}
+
+	// Walker of a root that can be signed with 'sk'.
+	if cipher.PubKeyFromSecKey(sk) != pk {
+		e = ErrKeyPairMismatch
+		return
+	}
+	r := c.LastRootSk(pk, sk)
+	if r == nil {
+		r = c.NewRoot(pk, sk)
+	}
+	return NewRootWalker(r, pk, sk, opts...)
+}
+
+// ReadOnly returns true if the walker has no secret key, and hence cannot mutate the root.
+func (w *RootWalker) ReadOnly() bool {
+	return w.rsk == (cipher.SecKey{})
+}
+
 // Size returns the size of the internal stack of walker.
 func (w *RootWalker) Size() int {
 	return len(w.stack)
@@ -41,6 +104,87 @@ func (w *RootWalker) Clear() {
 	w.stack = []*wrappedObj{}
 }
 
+// Publish signs the root with the walker's secret key and hands it to the node, which broadcasts it to subscribers.
+// This is required after mutations for other nodes to receive the changes, unless the walker is created with the
+// AutoPublish option. The node signs the root with the secret key attached to it, so the root is required to be
+// obtained with the walker's secret key attached (e.g. created with NewRoot, obtained with LastRootSk, or with
+// NewRootWalkerFromContainer), or ErrRootReadOnly is returned.
+func (w *RootWalker) Publish() error {
+	// Check root.
+	if w.r == nil {
+		return ErrRootNotFound
+	}
+
+	// Check secret key, and that the root belongs to the walker's key pair.
+	if w.rsk == (cipher.SecKey{}) {
+		return ErrNoSecretKey
+	}
+	if cipher.PubKeyFromSecKey(w.rsk) != w.rpk {
+		return ErrKeyPairMismatch
+	}
+	if w.r.Pub() != w.rpk {
+		return ErrRootKeyMismatch
+	}
+	if w.r.IsReadOnly() {
+		return ErrRootReadOnly
+	}
+
+	// Sign and broadcast. The attached secret key is the walker's, as it belongs to the walker's public key.
+	_, e := w.r.Touch()
+	return e
+}
+
+// Refresh replaces the walker's root with a newer version 'r' of the same root, and clears the internal stack.
+// If the walker verifies root signatures, the signature of 'r' is verified again before the next advance.
+func (w *RootWalker) Refresh(r *node.Root) error {
+	if r == nil {
+		return ErrRootNotFound
+	}
+	w.r = r
+	w.verified = false
+	w.Clear()
+	return nil
+}
+
+// Helper function. Obtains the root, verifying it's signature first if required.
+func (w *RootWalker) root() (*node.Root, error) {
+	if w.r == nil {
+		return nil, ErrRootNotFound
+	}
+	if w.verifySig && w.verified == false {
+		if e := w.verifyRoot(w.r); e != nil {
+			return nil, e
+		}
+		w.verified = true
+	}
+	return w.r, nil
+}
+
+// Helper function. Verifies that root 'r' is signed by the root's public key.
+func (w *RootWalker) verifyRoot(r *node.Root) error {
+	if e := cipher.VerifySignature(w.rpk, r.Sig(), cipher.SHA256(r.Hash())); e != nil {
+		return ErrBadRootSignature
+	}
+	return nil
+}
+
+// Helper function. Creates a pointer to a new object of the Go type provided for schema 'schemaName' with WithTypes.
+func (w *RootWalker) newObjOfSchema(schemaName string) (interface{}, error) {
+	t, has := w.types[schemaName]
+	if has == false {
+		return nil, ErrTypeNotProvided
+	}
+	return reflect.New(t).Interface(), nil
+}
+
+// Helper function. Publishes the root after a mutation if the walker is set to auto-publish.
+func (w *RootWalker) published() error {
+	if w.autoPublish == false {
+		return nil
+	}
+	return w.Publish()
+}
+
 // Helper function. Obtains top-most object from internal stack.
 func (w *RootWalker) peek() (*wrappedObj, error) {
 	if w.Size() == 0 {
@@ -49,34 +193,55 @@ func (w *RootWalker) peek() (*wrappedObj, error) {
 	return w.stack[w.Size()-1], nil
 }
 
+// Helper function. Obtains top-most object from internal stack for mutation.
+// Fails fast if the walker is read-only. Mutations of the top-most object obtain it through 'mutate', which uses this,
+// while mutations of the whole root check the walker through 'mutateRoot'.
+func (w *RootWalker) peekMutable() (*wrappedObj, error) {
+	if w.ReadOnly() {
+		return nil, ErrReadOnlyWalker
+	}
+	if _, e := w.root(); e != nil {
+		return nil, e
+	}
+	for _, obj := range w.stack {
+		if obj.p == nil {
+			return nil, ErrFrameNotDecoded
+		}
+	}
+	return w.peek()
+}
+
 // AdvanceFromRoot advances the walker to a child object of the root.
 // It uses a Finder implementation to find the child to advance to.
 // This function auto-clears the internal stack.
 // Input 'p' should be provided with a pointer to the object in which the chosen root's child should deserialize to.
+// If 'p' is nil, the object is advanced to lazily and is not deserialized until Decode is called.
 func (w *RootWalker) AdvanceFromRoot(p interface{}, finder func(v *skyobject.Value) bool) error {
 	// Clear the internal stack.
 	w.Clear()
 
 	// Check root.
-	r := w.r
-	if w.r == nil {
-		return ErrRootNotFound
+	r, e := w.root()
+	if e != nil {
+		return e
 	}
 
 	// Loop through direct children of root.
 	for i, dRef := range r.Refs() {
 		// See if it's the object needed with Finder.
-		v, e := r.ValueByDynamic(dRef)
+		v, e := w.valueByDynamic(r, dRef)
 		if e != nil {
 			return e
 		}
 		// If object is found, add to stack and return.
-		if finder(v) {
-			// Deserialize.
-			if e := encoder.DeserializeRaw(v.Data(), p); e != nil {
-				return e
+		if w.find(finder, v) {
+			// Deserialize, unless advancing lazily.
+			if p != nil {
+				if e := w.decode(dRef, v, p); e != nil {
+					return e
+				}
 			}
-			obj := w.newObj(v.Schema().Reference(), p, "", i)
+			obj := w.newObj(v, p, "", i)
 			w.stack = append(w.stack, obj)
 			return nil
 		}
@@ -87,11 +252,12 @@ func (w *RootWalker) AdvanceFromRoot(p interface{}, finder func(v *skyobject.Val
 // AdvanceFromRefsField advances from a field of name 'prevFieldName' and of type 'skyobject.References'.
 // It uses a Finder implementation to find the child to advance to.
 // Input 'p' should be provided with a pointer to the object in which the chosen child object should deserialize to.
+// If 'p' is nil, the object is advanced to lazily and is not deserialized until Decode is called.
 func (w *RootWalker) AdvanceFromRefsField(fieldName string, p interface{}, finder func(v *skyobject.Value) bool) error {
 	// Check root.
-	r := w.r
-	if w.r == nil {
-		return ErrRootNotFound
+	r, e := w.root()
+	if e != nil {
+		return e
 	}
 
 	// Obtain top-most object from internal stack.
@@ -121,18 +287,20 @@ func (w *RootWalker) AdvanceFromRefsField(fieldName string, p interface{}, finde
 			Schema: schema.Reference(),
 		}
 		// Obtain value from root.
-		v, e := r.ValueByDynamic(dynamic)
+		v, e := w.valueByDynamic(r, dynamic)
 		if e != nil {
 			return e
 		}
 		// See if it's the object with Finder.
-		if finder(v) {
-			// Deserialize.
-			if e := encoder.DeserializeRaw(v.Data(), p); e != nil {
-				return e
+		if w.find(finder, v) {
+			// Deserialize, unless advancing lazily.
+			if p != nil {
+				if e := w.decode(dynamic, v, p); e != nil {
+					return e
+				}
 			}
 			// Add to stack.
-			newObj := obj.generate(v.Schema().Reference(), p, fieldName, i)
+			newObj := obj.generate(v, p, fieldName, i)
 			w.stack = append(w.stack, newObj)
 			return nil
 		}
@@ -143,11 +311,12 @@ func (w *RootWalker) AdvanceFromRefsField(fieldName string, p interface{}, finde
 // AdvanceFromRefField advances from a field of name 'prevFieldName' and type 'skyobject.Reference'.
 // No Finder is required as field is a single reference.
 // Input 'p' should be provided with a pointer to the object in which the chosen child object should deserialize to.
+// If 'p' is nil, the object is advanced to lazily and is not deserialized until Decode is called.
 func (w *RootWalker) AdvanceFromRefField(fieldName string, p interface{}) error {
 	// Check root.
-	r := w.r
-	if w.r == nil {
-		return ErrRootNotFound
+	r, e := w.root()
+	if e != nil {
+		return e
 	}
 
 	// Obtain top-most object from internal stack.
@@ -175,17 +344,19 @@ func (w *RootWalker) AdvanceFromRefField(fieldName string, p interface{}) error
 		Schema: schema.Reference(),
 	}
 	// Obtain value from root.
-	v, e := r.ValueByDynamic(dynamic)
+	v, e := w.valueByDynamic(r, dynamic)
 	if e != nil {
 		return e
 	}
 
-	// Deserialize.
-	if e := encoder.DeserializeRaw(v.Data(), p); e != nil {
-		return e
+	// Deserialize, unless advancing lazily.
+	if p != nil {
+		if e := w.decode(dynamic, v, p); e != nil {
+			return e
+		}
 	}
 	// Add to internal stack.
-	newObj := obj.generate(v.Schema().Reference(), p, fieldName, -1)
+	newObj := obj.generate(v, p, fieldName, -1)
 	w.stack = append(w.stack, newObj)
 	return nil
 }
@@ -193,11 +364,12 @@ func (w *RootWalker) AdvanceFromRefField(fieldName string, p interface{}) error
 // AdvanceFromDynamicField advances from a field of name 'prevFieldName' and type 'skyobject.Dynamic'.
 // No Finder is required as field is a single reference.
 // Input 'p' should be provided with a pointer to the object in which the chosen child object should deserialize to.
+// If 'p' is nil, the object is advanced to lazily and is not deserialized until Decode is called.
 func (w *RootWalker) AdvanceFromDynamicField(fieldName string, p interface{}) error {
 	// Check root.
-	r := w.r
-	if w.r == nil {
-		return ErrRootNotFound
+	r, e := w.root()
+	if e != nil {
+		return e
 	}
 
 	// Obtain top-most object from internal stack.
@@ -214,21 +386,142 @@ func (w *RootWalker) AdvanceFromDynamicField(fieldName string, p interface{}) er
 	}
 
 	// Obtain value from root.
-	v, e := r.ValueByDynamic(fDyn)
+	v, e := w.valueByDynamic(r, fDyn)
 	if e != nil {
 		return e
 	}
 
-	// Deserialize.
-	if e := encoder.DeserializeRaw(v.Data(), p); e != nil {
-		return e
+	// Deserialize, unless advancing lazily.
+	if p != nil {
+		if e := w.decode(fDyn, v, p); e != nil {
+			return e
+		}
 	}
 	// Add to internal stack.
-	newObj := obj.generate(v.Schema().Reference(), p, fieldName, -1)
+	newObj := obj.generate(v, p, fieldName, -1)
 	w.stack = append(w.stack, newObj)
 	return nil
 }
 
+// Restore clears the internal stack, and advances the walker along 'path', as obtained from Path or FindAll.
+// Input 'ps' should be provided with a pointer for each step of the path, in which the objects along the path should
+// deserialize to. Nil pointers advance lazily to the respective objects. If the objects along the path no longer match
+// the path's schemas, ErrObjNotFound is returned.
+// On failure, the internal stack is left cleared.
+func (w *RootWalker) Restore(path Path, ps ...interface{}) error {
+	// Clear the internal stack.
+	w.Clear()
+
+	// Check root.
+	r, e := w.root()
+	if e != nil {
+		return e
+	}
+
+	// Check number of provided objects.
+	if len(ps) != len(path) {
+		return ErrPathLengthMismatch
+	}
+
+	// Advance along path.
+	for i, step := range path {
+		if e := w.advanceByStep(r, step, ps[i]); e != nil {
+			w.Clear()
+			return e
+		}
+	}
+	return nil
+}
+
+// Helper function. Advances the walker from the top-most object (or from the root if the internal stack is empty)
+// by a single step of a path.
+func (w *RootWalker) advanceByStep(r *node.Root, step PathStep, p interface{}) error {
+	// Obtain dynamic reference of child.
+	var dynamic skyobject.Dynamic
+	obj, e := w.peek()
+	if e != nil {
+		rDyns := r.Refs()
+		if step.Index < 0 || step.Index >= len(rDyns) {
+			return ErrObjNotFound
+		}
+		dynamic = rDyns[step.Index]
+	} else {
+		if dynamic, e = obj.getChild(step.FieldName, step.Index); e != nil {
+			return e
+		}
+	}
+
+	// Obtain value from root.
+	v, e := w.valueByDynamic(r, dynamic)
+	if e != nil {
+		return e
+	}
+	if step.Schema != "" && v.Schema().Name() != step.Schema {
+		return ErrObjNotFound
+	}
+
+	// Deserialize, unless advancing lazily.
+	if p != nil {
+		if e := w.decode(dynamic, v, p); e != nil {
+			return e
+		}
+	}
+	// Add to internal stack.
+	if obj == nil {
+		w.stack = append(w.stack, w.newObj(v, p, "", step.Index))
+	} else {
+		w.stack = append(w.stack, obj.generate(v, p, step.FieldName, step.Index))
+	}
+	return nil
+}
+
+// Decode deserializes the top-most object of the internal stack into 'p', which should be a pointer to an object of
+// the object's Go type. This is required for objects that were advanced to lazily before the walker can mutate them.
+func (w *RootWalker) Decode(p interface{}) error {
+	// Check root.
+	if _, e := w.root(); e != nil {
+		return e
+	}
+
+	// Obtain top-most object from internal stack.
+	obj, e := w.peek()
+	if e != nil {
+		return e
+	}
+
+	// Deserialize current value, as the object may be re-saved since advanced to.
+	dyn, e := obj.dynamic()
+	if e != nil {
+		return e
+	}
+	v, e := obj.value()
+	if e != nil {
+		return e
+	}
+	if e := w.decode(dyn, v, p); e != nil {
+		return e
+	}
+	obj.p = p
+	return nil
+}
+
+// Value returns the value of the top-most object of the internal stack, regardless of whether it is decoded.
+func (w *RootWalker) Value() (*skyobject.Value, error) {
+	// Check root.
+	if _, e := w.root(); e != nil {
+		return nil, e
+	}
+
+	// Obtain top-most object from internal stack.
+	obj, e := w.peek()
+	if e != nil {
+		return nil, e
+	}
+
+	// Obtain current value from root.
+	return obj.value()
+}
+
 // Retreat retreats one from the internal stack.
 func (w *RootWalker) Retreat() {
 	switch w.Size() {
@@ -245,28 +538,30 @@ func (w *RootWalker) Retreat() {
 // AppendToRefsField appends a reference to references field 'fieldName' of top-most object. The new reference will be
 // generated automatically by saving the object which 'p' points to. This recursively replaces all the associated
 // "references" of the object tree and hence, changes the root.
+// Unlike ReplaceInRefField and ReplaceInDynamicField, no 'changed' result is returned, as appending a reference always
+// changes the top-most object, and hence the root.
 func (w *RootWalker) AppendToRefsField(fieldName string, p interface{}) error {
-	// Obtain top-most object.
-	tObj, e := w.peek()
-	if e != nil {
-		return e
-	}
-
-	// Save new obj.
-	nRef := w.r.Save(p)
+	_, e := w.mutate("AppendToRefsField", fieldName, func(tObj *wrappedObj) (bool, error) {
+		// Save new obj.
+		nRef, e := w.save(p, w.Size())
+		if e != nil {
+			return false, e
+		}
 
-	// Edit top-most object.
-	tRefs, _, e := tObj.getFieldAsReferences(fieldName)
-	if e != nil {
-		return e
-	}
-	tRefs = append(tRefs, nRef)
-	if e := tObj.replaceReferencesField(fieldName, tRefs); e != nil {
-		return e
-	}
+		// Edit top-most object.
+		tRefs, _, e := tObj.getFieldAsReferences(fieldName)
+		if e != nil {
+			return false, e
+		}
+		tRefs = append(tRefs, nRef)
+		if e := tObj.replaceReferencesField(fieldName, tRefs); e != nil {
+			return false, e
+		}
 
-	// Recursively save.
-	_, e = tObj.save()
+		// Recursively save.
+		_, changed, e := tObj.save()
+		return changed, e
+	})
 	return e
 }
 
@@ -277,42 +572,65 @@ func (w *RootWalker) AppendToRefsField(fieldName string, p interface{}) error {
 // ReplaceInRefField replaces the reference field of the top-most object with a new reference; one that is automatically
 // generated when saving the object 'p' points to, in the container. This recursively replaces all the associated
 // "references" of the object tree and hence, changes the root.
-func (w *RootWalker) ReplaceInRefField(fieldName string, p interface{}) error {
-	// Obtain top-most object.
-	tObj, e := w.peek()
-	if e != nil {
-		return e
-	}
+// If the new reference equals the old one, nothing is re-saved and 'changed' is false.
+// Only the object along the path of the internal stack is replaced; other parents that reference the same object are
+// unchanged. Use ReplaceEverywhere to replace the object in all it's parents.
+func (w *RootWalker) ReplaceInRefField(fieldName string, p interface{}) (changed bool, e error) {
+	return w.mutate("ReplaceInRefField", fieldName, func(tObj *wrappedObj) (bool, error) {
+		// Obtain old reference, which is replaced.
+		oRef, oSchemaName, e := tObj.getFieldAsReference(fieldName)
+		if e != nil {
+			return false, e
+		}
 
-	// Save new obj.
-	nRef := w.r.Save(p)
-	if e := tObj.replaceReferenceField(fieldName, nRef); e != nil {
-		return e
-	}
+		// Save new obj. Stop if it is the same object.
+		nRef, e := w.save(p, w.Size())
+		if e != nil {
+			return false, e
+		}
+		if nRef == oRef {
+			return false, nil
+		}
+		if e := tObj.replaceReferenceField(fieldName, nRef); e != nil {
+			return false, e
+		}
+		if oSchema, _ := w.r.SchemaByName(oSchemaName); oSchema != nil {
+			w.displace(skyobject.Dynamic{Object: oRef, Schema: oSchema.Reference()})
+		}
 
-	// Recursively save.
-	_, e = tObj.save()
-	return e
+		// Recursively save.
+		_, changed, e := tObj.save()
+		return changed, e
+	})
 }
 
 // ReplaceInDynamicField functions the same as 'ReplaceInRefField'. However, it replaces a dynamic reference field other
 // than a static reference field.
-func (w *RootWalker) ReplaceInDynamicField(fieldName string, p interface{}) error {
-	// Obtain top-most object.
-	tObj, e := w.peek()
-	if e != nil {
-		return e
-	}
+func (w *RootWalker) ReplaceInDynamicField(fieldName string, p interface{}) (changed bool, e error) {
+	return w.mutate("ReplaceInDynamicField", fieldName, func(tObj *wrappedObj) (bool, error) {
+		// Obtain old dynamic reference, which is replaced.
+		oDyn, e := tObj.getFieldAsDynamic(fieldName)
+		if e != nil {
+			return false, e
+		}
 
-	// Save new object.
-	nDyn := w.r.Dynamic(p)
-	if e := tObj.replaceDynamicField(fieldName, nDyn); e != nil {
-		return e
-	}
+		// Save new object. Stop if it is the same object.
+		nDyn, e := w.saveDynamic(p, w.Size())
+		if e != nil {
+			return false, e
+		}
+		if nDyn == oDyn {
+			return false, nil
+		}
+		if e := tObj.replaceDynamicField(fieldName, nDyn); e != nil {
+			return false, e
+		}
+		w.displace(oDyn)
 
-	// Recursively save.
-	_, e = tObj.save()
-	return e
+		// Recursively save.
+		_, changed, e := tObj.save()
+		return changed, e
+	})
 }
 
 // String creates a readable string that shows information of the internal stack.
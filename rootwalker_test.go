@@ -7,6 +7,7 @@ import (
 	"testing"
 	"github.com/skycoin/cxo/node"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -102,6 +103,50 @@ func fillContainer1(c *node.Container, pk cipher.PubKey, sk cipher.SecKey) *node
 	return r
 }
 
+// newTestWalker creates a walker of a root filled with fillContainer1, of the key pair of genKeyPair. The client is to
+// be closed by the caller.
+func newTestWalker(t *testing.T, options ...Option) (*node.Client, *RootWalker) {
+	pk, sk := genKeyPair()
+	client := newClient()
+	w, e := NewRootWalker(fillContainer1(client.Container(), pk, sk), pk, sk, options...)
+	if e != nil {
+		client.Close()
+		t.Fatal("failed to create walker:", e)
+	}
+	return client, w
+}
+
+// findBy obtains a finder of the object of schema 'schemaName' whose string field 'fieldName' is 'value'.
+func findBy(schemaName, fieldName, value string) func(v *skyobject.Value) bool {
+	return func(v *skyobject.Value) (chosen bool) {
+		if v.Schema().Name() != schemaName {
+			return false
+		}
+		fv, e := v.FieldByName(fieldName)
+		if e != nil {
+			return false
+		}
+		s, _ := fv.String()
+		return s == value
+	}
+}
+
+// advanceToBoard advances the walker from the root to the board named 'name', deserialized into 'board'.
+func advanceToBoard(t *testing.T, w *RootWalker, name string, board *Board) {
+	if e := w.AdvanceFromRoot(board, findBy("Board", "Name", name)); e != nil {
+		t.Fatal("advance from root failed:", e)
+	}
+}
+
+// advanceToThread advances the walker from the root to thread 'threadName' of board 'boardName', deserialized into
+// 'board' and 'thread'.
+func advanceToThread(t *testing.T, w *RootWalker, boardName, threadName string, board *Board, thread *Thread) {
+	advanceToBoard(t, w, boardName, board)
+	if e := w.AdvanceFromRefsField("Threads", thread, findBy("Thread", "Name", threadName)); e != nil {
+		t.Fatal("advance from board to thread failed:", e)
+	}
+}
+
 func TestNewWalker(t *testing.T) {
 	pk, sk := genKeyPair()
 	client := newClient()
@@ -338,7 +383,7 @@ func TestWalker_ReplaceInRefField(t *testing.T) {
 		}
 		t.Log(w.String())
 
-		e = w.ReplaceInRefField("Creator", Person{"Donald Trump", 70})
+		_, e = w.ReplaceInRefField("Creator", Person{"Donald Trump", 70})
 		if e != nil {
 			t.Error("failed to replace:", e)
 		}
@@ -381,7 +426,7 @@ func TestWalker_ReplaceInRefField(t *testing.T) {
 			t.Log(p)
 		}
 
-		e = w.ReplaceInRefField("Creator", Person{Name: "Bruce Lee", Age: 77})
+		_, e = w.ReplaceInRefField("Creator", Person{Name: "Bruce Lee", Age: 77})
 		if e != nil {
 			t.Error("failed to replace", e)
 		}
@@ -394,6 +439,32 @@ func TestWalker_ReplaceInRefField(t *testing.T) {
 			t.Log(p)
 		}
 	})
+	t.Run("unchanged", func(t *testing.T) {
+		client, w := newTestWalker(t)
+		defer client.Close()
+		advanceToBoard(t, w, "Talk", &Board{})
+
+		mutations := 0
+		w.OnAfterMutate(func(m Mutation) { mutations++ })
+
+		// Replacing the creator with an identical person leaves the root unchanged.
+		oldRoot := w.r.Hash()
+		changed, e := w.ReplaceInRefField("Creator", Person{"Eric", 23})
+		if e != nil {
+			t.Error("failed to replace:", e)
+		}
+		if changed || w.r.Hash() != oldRoot || mutations != 0 {
+			t.Error("expected root to be unchanged")
+		}
+
+		changed, e = w.ReplaceInRefField("Creator", Person{"Donald Trump", 70})
+		if e != nil {
+			t.Error("failed to replace:", e)
+		}
+		if changed == false || w.r.Hash() == oldRoot || mutations != 1 {
+			t.Error("expected root to be changed")
+		}
+	})
 }
 
 func TestWalker_ReplaceInDynamicField(t *testing.T) {
@@ -424,7 +495,7 @@ func TestWalker_ReplaceInDynamicField(t *testing.T) {
 			t.Log(p)
 		}
 
-		e = w.ReplaceInDynamicField("Featured", Post{Title: "Good Game", Body: "Yeah, this is fun."})
+		_, e = w.ReplaceInDynamicField("Featured", Post{Title: "Good Game", Body: "Yeah, this is fun."})
 		if e != nil {
 			t.Error("replace failed:", e)
 		}
@@ -437,4 +508,400 @@ func TestWalker_ReplaceInDynamicField(t *testing.T) {
 			t.Log(p)
 		}
 	})
-}
\ No newline at end of file
+}
+
+func TestWalker_Publish(t *testing.T) {
+	t.Run("with secret key", func(t *testing.T) {
+		client, w := newTestWalker(t, AutoPublish())
+		defer client.Close()
+		advanceToBoard(t, w, "Talk", &Board{})
+
+		oldSig := w.r.Sig()
+		e := w.AppendToRefsField("Threads", Thread{Name: "Published Thread"})
+		if e != nil {
+			t.Error("append with auto-publish failed:", e)
+		}
+		if w.r.Sig() == oldSig {
+			t.Error("expected root to be signed again after auto-publish")
+		}
+		if e := cipher.VerifySignature(w.rpk, w.r.Sig(), cipher.SHA256(w.r.Hash())); e != nil {
+			t.Error("expected published root to be signed by walker's key:", e)
+		}
+		if e := w.Publish(); e != nil {
+			t.Error("publish failed:", e)
+		}
+	})
+	t.Run("root of other key", func(t *testing.T) {
+		pk, sk := genKeyPair()
+		otherPK, otherSK := cipher.GenerateDeterministicKeyPair([]byte("b"))
+		client := newClient()
+		defer client.Close()
+		w, _ := NewRootWalker(fillContainer1(client.Container(), otherPK, otherSK), pk, sk)
+
+		if e := w.Publish(); e != ErrRootKeyMismatch {
+			t.Error("expected error", ErrRootKeyMismatch, "got", e)
+		}
+	})
+	t.Run("root without secret key", func(t *testing.T) {
+		pk, sk := genKeyPair()
+		client := newClient()
+		defer client.Close()
+		c := client.Container()
+		fillContainer1(c, pk, sk)
+		w, _ := NewRootWalker(c.LastRoot(pk), pk, sk)
+
+		if e := w.Publish(); e != ErrRootReadOnly {
+			t.Error("expected error", ErrRootReadOnly, "got", e)
+		}
+	})
+	t.Run("without secret key", func(t *testing.T) {
+		pk, sk := genKeyPair()
+		client := newClient()
+		defer client.Close()
+		w, _ := NewRootWalker(fillContainer1(client.Container(), pk, sk), pk, cipher.SecKey{})
+
+		if e := w.Publish(); e != ErrNoSecretKey {
+			t.Error("expected error", ErrNoSecretKey, "got", e)
+		}
+	})
+}
+
+func TestWalker_VerifyRootSignature(t *testing.T) {
+	t.Run("good signature", func(t *testing.T) {
+		client, w := newTestWalker(t, VerifyRootSignature())
+		defer client.Close()
+		advanceToBoard(t, w, "Talk", &Board{})
+	})
+	t.Run("bad signature", func(t *testing.T) {
+		pk, sk := genKeyPair()
+		otherPK, _ := cipher.GenerateDeterministicKeyPair([]byte("b"))
+		client := newClient()
+		defer client.Close()
+		w, _ := NewRootWalker(fillContainer1(client.Container(), pk, sk), otherPK, cipher.SecKey{}, VerifyRootSignature())
+
+		board := &Board{}
+		e := w.AdvanceFromRoot(board, func(v *skyobject.Value) (chosen bool) {
+			return v.Schema().Name() == "Board"
+		})
+		if e != ErrBadRootSignature {
+			t.Error("expected error", ErrBadRootSignature, "got", e)
+		}
+	})
+	t.Run("unsigned root", func(t *testing.T) {
+		pk, sk := genKeyPair()
+		client := newClient()
+		defer client.Close()
+
+		// The root belongs to the walker's public key, but is never signed.
+		w, _ := NewRootWalker(client.Container().NewRoot(pk, sk), pk, cipher.SecKey{}, VerifyRootSignature())
+		if w.r.Pub() != pk {
+			t.Fatal("expected root of walker's public key")
+		}
+		e := w.AdvanceFromRoot(&Board{}, func(v *skyobject.Value) (chosen bool) {
+			return true
+		})
+		if e != ErrBadRootSignature {
+			t.Error("expected error", ErrBadRootSignature, "got", e)
+		}
+	})
+}
+
+func TestNewRootWalkerFromContainer(t *testing.T) {
+	t.Run("existing root", func(t *testing.T) {
+		pk, sk := genKeyPair()
+		client := newClient()
+		defer client.Close()
+		fillContainer1(client.Container(), pk, sk)
+
+		w, e := NewRootWalkerFromContainer(client.Container(), pk, cipher.SecKey{})
+		if e != nil {
+			t.Error("failed to create walker:", e)
+		}
+		advanceToBoard(t, w, "Talk", &Board{})
+	})
+	t.Run("existing root with secret key", func(t *testing.T) {
+		pk, sk := genKeyPair()
+		client := newClient()
+		defer client.Close()
+		fillContainer1(client.Container(), pk, sk)
+
+		w, e := NewRootWalkerFromContainer(client.Container(), pk, sk)
+		if e != nil {
+			t.Fatal("failed to create walker:", e)
+		}
+		if len(w.r.Refs()) != 2 {
+			t.Error("expected existing root, got", len(w.r.Refs()), "references")
+		}
+		advanceToBoard(t, w, "Talk", &Board{})
+		if e := w.AppendToRefsField("Threads", Thread{Name: "New Thread"}); e != nil {
+			t.Error("failed to append:", e)
+		}
+		if e := w.Publish(); e != nil {
+			t.Error("publish failed:", e)
+		}
+		if e := cipher.VerifySignature(w.rpk, w.r.Sig(), cipher.SHA256(w.r.Hash())); e != nil {
+			t.Error("expected published root to be signed by walker's key:", e)
+		}
+	})
+	t.Run("mismatched secret key", func(t *testing.T) {
+		pk, _ := genKeyPair()
+		_, otherSK := cipher.GenerateDeterministicKeyPair([]byte("b"))
+		client := newClient()
+		defer client.Close()
+
+		if _, e := NewRootWalkerFromContainer(client.Container(), pk, otherSK); e != ErrKeyPairMismatch {
+			t.Error("expected error", ErrKeyPairMismatch, "got", e)
+		}
+	})
+	t.Run("missing root", func(t *testing.T) {
+		pk, sk := genKeyPair()
+		client := newClient()
+		defer client.Close()
+
+		if _, e := NewRootWalkerFromContainer(client.Container(), pk, cipher.SecKey{}); e != ErrRootNotFound {
+			t.Error("expected error", ErrRootNotFound, "got", e)
+		}
+		w, e := NewRootWalkerFromContainer(client.Container(), pk, sk)
+		if e != nil {
+			t.Error("failed to create walker with new root:", e)
+		}
+		if len(w.r.Refs()) != 0 {
+			t.Error("expected empty root")
+		}
+	})
+}
+
+func TestWalker_ReadOnly(t *testing.T) {
+	pk, sk := genKeyPair()
+	client := newClient()
+	defer client.Close()
+	w, _ := NewReadOnlyRootWalker(fillContainer1(client.Container(), pk, sk), pk)
+	advanceToBoard(t, w, "Talk", &Board{})
+
+	if e := w.AppendToRefsField("Threads", Thread{Name: "New Thread"}); e != ErrReadOnlyWalker {
+		t.Error("expected error", ErrReadOnlyWalker, "got", e)
+	}
+	if _, e := w.ReplaceInRefField("Creator", Person{"Donald Trump", 70}); e != ErrReadOnlyWalker {
+		t.Error("expected error", ErrReadOnlyWalker, "got", e)
+	}
+	if _, e := w.ReplaceInDynamicField("Featured", Post{Title: "Good Game"}); e != ErrReadOnlyWalker {
+		t.Error("expected error", ErrReadOnlyWalker, "got", e)
+	}
+}
+
+func TestWalker_Garbage(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+
+	advanceToThread(t, w, "Talk", "Greetings", &Board{}, &Thread{})
+
+	// Evan is still the author of posts, so only the old thread and board become unreachable.
+	_, e := w.ReplaceInRefField("Creator", Person{Name: "Bruce Lee", Age: 77})
+	if e != nil {
+		t.Error("failed to replace", e)
+	}
+	report, e := w.Garbage()
+	if e != nil {
+		t.Fatal("garbage report failed:", e)
+	}
+	if report.Count != 2 || report.Schemas["Board"].Count != 1 || report.Schemas["Thread"].Count != 1 {
+		t.Error("unexpected garbage report:", report)
+	}
+
+	// Bruce Lee becomes unreachable after being replaced.
+	_, e = w.ReplaceInRefField("Creator", Person{Name: "Evan", Age: 21})
+	if e != nil {
+		t.Error("failed to replace", e)
+	}
+	report, e = w.Garbage()
+	if e != nil {
+		t.Fatal("garbage report failed:", e)
+	}
+	if report.Schemas["Person"] == nil || report.Schemas["Person"].Count != 1 {
+		t.Error("expected 1 unreachable person:", report)
+	}
+
+	// Objects replaced again are recorded once.
+	if _, e := w.ReplaceInRefField("Creator", Person{Name: "Bruce Lee", Age: 77}); e != nil {
+		t.Error("failed to replace", e)
+	}
+	if len(w.displaced) != 6 {
+		t.Error("expected 6 recorded objects, got", len(w.displaced))
+	}
+
+	w.ResetGarbage()
+	if report, _ := w.Garbage(); report.Count != 0 {
+		t.Error("expected empty report after reset")
+	}
+}
+
+func TestWalker_Frames(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+
+	if _, e := w.Current(); e != ErrEmptyInternalStack {
+		t.Error("expected error", ErrEmptyInternalStack, "got", e)
+	}
+
+	board := &Board{}
+	advanceToBoard(t, w, "Talk", board)
+	if _, e := w.Parent(); e != ErrNoParentFrame {
+		t.Error("expected error", ErrNoParentFrame, "got", e)
+	}
+	thread := &Thread{}
+	if e := w.AdvanceFromRefsField("Threads", thread, findBy("Thread", "Name", "Expressions")); e != nil {
+		t.Fatal("advance from board to thread failed:", e)
+	}
+
+	frames := w.Frames()
+	if len(frames) != 2 {
+		t.Fatal("expected 2 frames, got", len(frames))
+	}
+	if f := frames[1]; f.Depth != 1 || f.Schema != "Thread" || f.FieldName != "Threads" || f.Index != 1 {
+		t.Error("unexpected frame:", f)
+	}
+	if frames[1].Ref != board.Threads[1] {
+		t.Error("frame reference does not match board's thread reference")
+	}
+	if cur, _ := w.Current(); cur.Value.(Thread).Name != "Expressions" {
+		t.Error("unexpected current frame:", cur)
+	}
+	if root, _ := w.Root(); root.Value.(Board).Name != "Talk" {
+		t.Error("unexpected root frame:", root)
+	}
+
+	// Frames are not affected by mutations.
+	if _, e := w.ReplaceInRefField("Creator", Person{"Donald Trump", 70}); e != nil {
+		t.Error("failed to replace:", e)
+	}
+	if frames[1].Value.(Thread).Creator == thread.Creator {
+		t.Error("frame was affected by mutation")
+	}
+}
+
+func TestWalker_Render(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+
+	advanceToThread(t, w, "Talk", "Greetings", &Board{}, &Thread{})
+
+	t.Run("compact", func(t *testing.T) {
+		out, e := w.Render(RenderOptions{Compact: true})
+		if e != nil {
+			t.Error("render failed:", e)
+		}
+		if out != "Root.Refs[1] > Board.Threads[0]" {
+			t.Error("unexpected compact render:", out)
+		}
+	})
+	t.Run("references", func(t *testing.T) {
+		out, e := w.Render(RenderOptions{HexRefs: true, ResolveDynamic: true})
+		if e != nil {
+			t.Error("render failed:", e)
+		}
+		t.Log("\n" + out)
+		for _, want := range []string{`Name: "Greetings"`, `Creator: &Person#`, `Featured: &Dynamic(Person)#`} {
+			if strings.Contains(out, want) == false {
+				t.Error("expected render to contain", want)
+			}
+		}
+	})
+	t.Run("inlined", func(t *testing.T) {
+		out, e := w.Render(RenderOptions{Depth: 1})
+		if e != nil {
+			t.Error("render failed:", e)
+		}
+		t.Log("\n" + out)
+		for _, want := range []string{`Creator: Person {`, `Name: "Evan"`, `Posts: [`} {
+			if strings.Contains(out, want) == false {
+				t.Error("expected render to contain", want)
+			}
+		}
+	})
+	t.Run("colored", func(t *testing.T) {
+		out, e := w.Render(RenderOptions{Color: true})
+		if e != nil {
+			t.Error("render failed:", e)
+		}
+		t.Log("\n" + out)
+		if strings.Contains(out, colorSchema+"Thread"+colorReset) == false {
+			t.Error("expected colored render to contain ANSI escape codes")
+		}
+		plain, e := w.Render(RenderOptions{})
+		if e != nil {
+			t.Error("render failed:", e)
+		}
+		if strings.Contains(plain, "\x1b[") {
+			t.Error("expected render without color to contain no ANSI escape codes")
+		}
+	})
+}
+
+func TestWalker_LazyAdvance(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+
+	// Advance lazily to board and thread.
+	if e := w.AdvanceFromRoot(nil, findBy("Board", "Name", "Talk")); e != nil {
+		t.Fatal("advance lazily from root failed:", e)
+	}
+	if e := w.AdvanceFromRefsField("Threads", nil, findBy("Thread", "Name", "Expressions")); e != nil {
+		t.Fatal("advance lazily from board to thread failed:", e)
+	}
+	if cur, _ := w.Current(); cur.Value != nil {
+		t.Error("expected lazy frame to have no value, got", cur.Value)
+	}
+	v, e := w.Value()
+	if e != nil {
+		t.Fatal("failed to obtain value:", e)
+	}
+	if fv, _ := v.FieldByName("Name"); fv == nil {
+		t.Error("expected value of thread")
+	} else if s, _ := fv.String(); s != "Expressions" {
+		t.Error("unexpected thread name:", s)
+	}
+
+	// Mutations require all objects of the internal stack to be decoded.
+	if _, e := w.ReplaceInRefField("Creator", Person{"Donald Trump", 70}); e != ErrFrameNotDecoded {
+		t.Error("expected error", ErrFrameNotDecoded, "got", e)
+	}
+	thread := &Thread{}
+	if e := w.Decode(thread); e != nil {
+		t.Error("failed to decode thread:", e)
+	}
+	if thread.Name != "Expressions" {
+		t.Error("unexpected thread name:", thread.Name)
+	}
+	if _, e := w.ReplaceInRefField("Creator", Person{"Donald Trump", 70}); e != ErrFrameNotDecoded {
+		t.Error("expected error", ErrFrameNotDecoded, "got", e)
+	}
+	w.Retreat()
+	if e := w.Decode(&Board{}); e != nil {
+		t.Error("failed to decode board:", e)
+	}
+	if e := w.AdvanceFromRefsField("Threads", thread, findBy("Thread", "Name", "Expressions")); e != nil {
+		t.Fatal("advance from board to thread failed:", e)
+	}
+	if _, e := w.ReplaceInRefField("Creator", Person{"Donald Trump", 70}); e != nil {
+		t.Error("failed to replace:", e)
+	}
+
+	// Decoding a mutated object decodes it's current value, and later mutations keep the earlier changes.
+	decoded := &Thread{}
+	if e := w.Decode(decoded); e != nil {
+		t.Fatal("failed to decode thread:", e)
+	}
+	if decoded.Creator != thread.Creator {
+		t.Error("expected decoded thread to have the replaced creator")
+	}
+	if e := w.AppendToRefsField("Posts", Post{Title: "After decode"}); e != nil {
+		t.Error("failed to append:", e)
+	}
+	current := &Thread{}
+	if e := w.Decode(current); e != nil {
+		t.Fatal("failed to decode thread:", e)
+	}
+	if current.Creator != thread.Creator || len(current.Posts) != len(thread.Posts)+1 {
+		t.Error("expected thread to have the replaced creator and the appended post")
+	}
+}
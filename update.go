@@ -0,0 +1,43 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+)
+
+// UpdateFunc updates object 'p' in place, and reports whether it changed.
+type UpdateFunc func(p interface{}) (changed bool, err error)
+
+// UpdateAll updates every object of schema 'schemaName' in the root's tree with 'fn'. Each object is deserialized into
+// a pointer created with 'newP' before it is passed to 'fn'. Only the objects that 'fn' changes are re-saved, along with
+// their ancestors, and the root is changed once at the end. E.g. to anonymize every person, or lowercase the names of
+// all threads. The Go types of all re-saved ancestors are required to be provided with WithTypes; they are checked
+// before anything is saved, and ErrTypeNotProvided is returned otherwise.
+// The internal stack is restored along the same path afterwards; if the path no longer exists (e.g. an updated object
+// has fewer references), the internal stack is cleared and ErrStackCleared is returned along with the report.
+func (w *RootWalker) UpdateAll(schemaName string, newP func() interface{}, fn UpdateFunc) (
+	report *RewriteReport, e error,
+) {
+	var restoreErr error
+	e = w.mutateRoot("UpdateAll", func(r *node.Root) (bool, error) {
+		if newP == nil || fn == nil {
+			return false, ErrFuncNotProvided
+		}
+		rw := w.newRewriter(r)
+		rw.schemaName = schemaName
+		rw.newP = newP
+		rw.transform = func(p interface{}) (interface{}, bool, error) {
+			changed, e := fn(p)
+			return p, changed, e
+		}
+		report = rw.report
+		changed, e := rw.rewriteRoot()
+		if changed {
+			restoreErr = w.restoreStack()
+		}
+		return changed, e
+	})
+	if e != nil {
+		return nil, e
+	}
+	return report, restoreErr
+}
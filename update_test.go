@@ -0,0 +1,95 @@
+package skywalker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWalker_UpdateAll(t *testing.T) {
+	client, w := newTestWalker(t, WithTypes(testTypes))
+	defer client.Close()
+
+	mutations := 0
+	w.OnAfterMutate(func(m Mutation) { mutations++ })
+
+	// Lowercase the names of all threads. Thread "Testing" is only under board "Test".
+	report, e := w.UpdateAll("Thread", func() interface{} { return &Thread{} }, func(p interface{}) (bool, error) {
+		thread := p.(*Thread)
+		if thread.Name != "Testing" {
+			return false, nil
+		}
+		thread.Name = strings.ToLower(thread.Name)
+		return true, nil
+	})
+	if e != nil {
+		t.Fatal("failed to update:", e)
+	}
+	if report.Changed != 1 || report.Saved["Thread"] != 1 || report.Saved["Board"] != 1 {
+		t.Error("expected only thread and board to be re-saved, got", report)
+	}
+	if mutations != 1 {
+		t.Error("expected root to change once, got", mutations)
+	}
+
+	thread := &Thread{}
+	e = w.Restore(Path{{Index: 0, Schema: "Board"}, {FieldName: "Threads", Index: 0, Schema: "Thread"}}, &Board{}, thread)
+	if e != nil {
+		t.Fatal("failed to restore:", e)
+	}
+	if thread.Name != "testing" {
+		t.Error("expected thread name to be lowercased, got", thread.Name)
+	}
+
+	// Updates without changes leave the root unchanged.
+	report, e = w.UpdateAll("Person", func() interface{} { return &Person{} }, func(p interface{}) (bool, error) {
+		return false, nil
+	})
+	if e != nil {
+		t.Fatal("failed to update:", e)
+	}
+	if report.Changed != 0 || mutations != 1 {
+		t.Error("expected root to be unchanged")
+	}
+
+	// Nothing is updated without functions, or without the Go types of the re-saved ancestors.
+	if _, e := w.UpdateAll("Person", nil, nil); e != ErrFuncNotProvided {
+		t.Error("expected error", ErrFuncNotProvided, "got", e)
+	}
+	bare, _ := NewRootWalker(w.r, w.rpk, w.rsk)
+	if _, e := bare.UpdateAll("Person", func() interface{} { return &Person{} }, func(p interface{}) (bool, error) {
+		return true, nil
+	}); e != ErrTypeNotProvided {
+		t.Error("expected error", ErrTypeNotProvided, "got", e)
+	}
+	if mutations != 1 || len(bare.displaced) != 0 {
+		t.Error("expected root to be unchanged")
+	}
+
+	// Errors abort the update.
+	errAbort := errors.New("abort")
+	if _, e := w.UpdateAll("Person", func() interface{} { return &Person{} }, func(p interface{}) (bool, error) {
+		return false, errAbort
+	}); e != errAbort {
+		t.Error("expected error", errAbort, "got", e)
+	}
+
+	// Removing the posts of thread "testing" removes the path to post "Test".
+	if e := w.AdvanceFromRefsField("Posts", &Post{}, findBy("Post", "Title", "Test")); e != nil {
+		t.Fatal("advance from thread to post failed:", e)
+	}
+	report, e = w.UpdateAll("Thread", func() interface{} { return &Thread{} }, func(p interface{}) (bool, error) {
+		thread := p.(*Thread)
+		if thread.Name != "testing" {
+			return false, nil
+		}
+		thread.Posts = nil
+		return true, nil
+	})
+	if e != ErrStackCleared {
+		t.Error("expected error", ErrStackCleared, "got", e)
+	}
+	if report == nil || report.Changed != 1 || w.Size() != 0 {
+		t.Error("expected thread to be updated, and internal stack to be cleared")
+	}
+}
@@ -0,0 +1,98 @@
+package skywalker
+
+import (
+	"fmt"
+	"github.com/skycoin/cxo/node"
+	"reflect"
+)
+
+// Validator checks object 'p' before it is saved, where 'p' is always a pointer to the object.
+// Returning an error aborts the mutation that saves the object.
+type Validator func(p interface{}) error
+
+// Validators is a registry of validators, keyed by schema name or by Go type. It may be shared between walkers, but
+// validators should not be registered while walkers use it.
+type Validators struct {
+	schemas map[string][]Validator
+	types   map[reflect.Type][]Validator
+}
+
+// NewValidators creates an empty registry of validators.
+func NewValidators() *Validators {
+	return &Validators{
+		schemas: make(map[string][]Validator),
+		types:   make(map[reflect.Type][]Validator),
+	}
+}
+
+// Schema registers validator 'v' for objects of schema 'schemaName'. The schema names of objects are obtained from the
+// Go types provided with WithTypes, or otherwise, from the registry of the root the objects are saved to.
+func (vs *Validators) Schema(schemaName string, v Validator) {
+	vs.schemas[schemaName] = append(vs.schemas[schemaName], v)
+}
+
+// Type registers validator 'v' for objects of the Go type of 'i', or of the Go type 'i' points to. E.g.
+// `vs.Type(Post{}, v)` or `vs.Type((*Post)(nil), v)`. ErrTypeNotProvided is returned if 'i' is nil.
+func (vs *Validators) Type(i interface{}, v Validator) error {
+	t := reflect.TypeOf(i)
+	if t == nil {
+		return ErrTypeNotProvided
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	vs.types[t] = append(vs.types[t], v)
+	return nil
+}
+
+// ValidationError occurs when an object fails validation before it is saved.
+type ValidationError struct {
+	Schema string // Schema name of the object.
+	Err    error  // Error returned by the validator.
+}
+
+// Error returns a description of the validation error.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation of %s failed: %v", e.Schema, e.Err)
+}
+
+// Helper function. Runs the validators of object 'p', those of it's Go type first. 'r' is the root the object is
+// saved to.
+func (w *RootWalker) validate(r *node.Root, p interface{}) error {
+	if w.validators == nil {
+		return nil
+	}
+
+	// Obtain pointer to object.
+	pv := reflect.ValueOf(p)
+	if pv.Kind() != reflect.Ptr {
+		cp := reflect.New(pv.Type())
+		cp.Elem().Set(pv)
+		pv = cp
+	}
+	t := pv.Elem().Type()
+	schemaName := w.schemaNameOfType(r, t)
+
+	// Run validators.
+	for _, v := range append(w.validators.types[t], w.validators.schemas[schemaName]...) {
+		if e := v(pv.Interface()); e != nil {
+			return &ValidationError{Schema: schemaName, Err: e}
+		}
+	}
+	return nil
+}
+
+// Helper function. Obtains the schema name of Go type 't', as provided with WithTypes, or otherwise, as registered in
+// the registry of root 'r'. The name is empty if the Go type is not registered.
+func (w *RootWalker) schemaNameOfType(r *node.Root, t reflect.Type) string {
+	for schemaName, wt := range w.types {
+		if wt == t {
+			return schemaName
+		}
+	}
+	s, e := r.Registry().SchemaByInterface(reflect.New(t).Elem().Interface())
+	if e != nil {
+		return ""
+	}
+	return s.Name()
+}
@@ -0,0 +1,64 @@
+package skywalker
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWalker_Validators(t *testing.T) {
+	locked := false
+	errNoTitle := errors.New("post has no title")
+	errLocked := errors.New("board is locked")
+	vs := NewValidators()
+	e := vs.Type((*Post)(nil), func(p interface{}) error {
+		if p.(*Post).Title == "" {
+			return errNoTitle
+		}
+		return nil
+	})
+	if e != nil {
+		t.Fatal("failed to register validator of post:", e)
+	}
+	if e := vs.Type(nil, func(p interface{}) error { return nil }); e != ErrTypeNotProvided {
+		t.Error("expected error", ErrTypeNotProvided, "got", e)
+	}
+	vs.Schema("Board", func(p interface{}) error {
+		if locked {
+			return errLocked
+		}
+		return nil
+	})
+	client, w := newTestWalker(t, WithValidators(vs))
+	defer client.Close()
+
+	thread := &Thread{}
+	advanceToThread(t, w, "Talk", "Greetings", &Board{}, thread)
+	oldRoot := w.r.Hash()
+
+	// Posts without titles are rejected.
+	e = w.AppendToRefsField("Posts", Post{Body: "No title."})
+	if ve, ok := e.(*ValidationError); ok == false || ve.Schema != "Post" || ve.Err != errNoTitle {
+		t.Error("expected validation error of post, got", e)
+	}
+
+	// Re-saved ancestors are validated too, and the internal stack is left as it was.
+	locked = true
+	e = w.AppendToRefsField("Posts", Post{Title: "Hi again"})
+	if ve, ok := e.(*ValidationError); ok == false || ve.Schema != "Board" || ve.Err != errLocked {
+		t.Error("expected validation error of board, got", e)
+	}
+	if w.r.Hash() != oldRoot {
+		t.Error("expected root to be unchanged")
+	}
+	if w.Size() != 2 || len(thread.Posts) != 3 {
+		t.Error("expected internal stack to be unchanged")
+	}
+
+	locked = false
+	if e := w.AppendToRefsField("Posts", Post{Title: "Hi again"}); e != nil {
+		t.Error("failed to append:", e)
+	}
+	if len(thread.Posts) != 4 {
+		t.Error("expected post to be appended")
+	}
+}
@@ -0,0 +1,137 @@
+package skywalker
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/skycoin/cxo/skyobject"
+	"github.com/skycoin/skycoin/src/cipher"
+	"reflect"
+)
+
+// Go types of values of basic kinds, used to decode them.
+var basicKindTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+	reflect.String:  reflect.TypeOf(""),
+}
+
+// jsonField represents a field of a jsonObject.
+type jsonField struct {
+	name  string
+	value interface{}
+}
+
+// jsonObject represents a JSON object which keeps the order of it's fields, so that objects are encoded in the
+// order of their schema's fields.
+type jsonObject []jsonField
+
+// MarshalJSON implements json.Marshaler.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, e := json.Marshal(f.name)
+		if e != nil {
+			return nil, e
+		}
+		value, e := json.Marshal(f.value)
+		if e != nil {
+			return nil, e
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Helper function. Obtains the hex representation of reference 'ref'.
+func refHex(ref skyobject.Reference) string {
+	return cipher.SHA256(ref).Hex()
+}
+
+// Helper function. Decodes value 'v' into a Go value that is readable without the schema; basic kinds decode to their
+// Go types, arrays and slices decode to '[]interface{}', and structs decode to a 'jsonObject' of their fields.
+// References held in fields of nested structs decode to their hex representations.
+func (w *RootWalker) plainValue(v *skyobject.Value) (interface{}, error) {
+	switch k := v.Kind(); k {
+	case reflect.Slice, reflect.Array:
+		l, e := v.Len()
+		if e != nil {
+			return nil, e
+		}
+		out := make([]interface{}, l)
+		for i := range out {
+			ev, e := v.Index(i)
+			if e != nil {
+				return nil, e
+			}
+			if out[i], e = w.plainValue(ev); e != nil {
+				return nil, e
+			}
+		}
+		return out, nil
+	case reflect.Struct:
+		var out jsonObject
+		for _, f := range v.Schema().Fields() {
+			fv, e := v.FieldByName(f.Name())
+			if e != nil {
+				return nil, e
+			}
+			var value interface{}
+			switch kindOfField(f) {
+			case referencesField:
+				var refs skyobject.References
+				if e := w.deserialize(fv.Data(), &refs); e != nil {
+					return nil, e
+				}
+				hexes := make([]string, len(refs))
+				for i, ref := range refs {
+					hexes[i] = refHex(ref)
+				}
+				value = hexes
+			case referenceField:
+				var ref skyobject.Reference
+				if e := w.deserialize(fv.Data(), &ref); e != nil {
+					return nil, e
+				}
+				value = refHex(ref)
+			case dynamicField:
+				var dyn skyobject.Dynamic
+				if e := w.deserialize(fv.Data(), &dyn); e != nil {
+					return nil, e
+				}
+				value = refHex(dyn.Object)
+			default:
+				if value, e = w.plainValue(fv); e != nil {
+					return nil, e
+				}
+			}
+			out = append(out, jsonField{f.Name(), value})
+		}
+		return out, nil
+	default:
+		t, has := basicKindTypes[k]
+		if has == false {
+			return nil, ErrFieldHasWrongType
+		}
+		p := reflect.New(t)
+		if e := w.deserialize(v.Data(), p.Interface()); e != nil {
+			return nil, e
+		}
+		return p.Elem().Interface(), nil
+	}
+}
@@ -0,0 +1,68 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+)
+
+// Visitor is called by Walk for each object of the root's tree.
+// It receives the path of the object, the object's schema and value, and the depth of the object, where direct
+// children of the root have a depth of 0.
+// Returning ErrSkipChildren skips the children of the object, and returning ErrStopWalk stops the walk without error.
+// Returning any other error stops the walk, and the error is returned by Walk.
+type Visitor func(path Path, s skyobject.Schema, v *skyobject.Value, depth int) error
+
+// Walk walks through every object of the root's tree in depth-first order, calling 'visit' for each object.
+// It follows fields of type 'skyobject.References', 'skyobject.Reference' (using their schema tags) and
+// 'skyobject.Dynamic'. Objects referenced from multiple places are visited once for each path.
+// The internal stack is not used nor changed.
+func (w *RootWalker) Walk(visit Visitor) error {
+	// Check root.
+	r, e := w.root()
+	if e != nil {
+		return e
+	}
+
+	// Walk through direct children of root.
+	for _, c := range rootChildRefs(r) {
+		if e := w.walkObj(r, Path{}, c, visit); e != nil {
+			if e == ErrStopWalk {
+				return nil
+			}
+			return e
+		}
+	}
+	return nil
+}
+
+// Helper function. Visits child 'c' of the object at path 'prevPath', and recursively walks it's children.
+func (w *RootWalker) walkObj(r *node.Root, prevPath Path, c childRef, visit Visitor) error {
+	// Obtain value from root.
+	v, e := w.valueByDynamic(r, c.dyn)
+	if e != nil {
+		return e
+	}
+	s := v.Schema()
+	path := prevPath.extend(c.fieldName, c.index, s.Name())
+
+	// Visit.
+	switch e := visit(path, s, v, len(prevPath)); e {
+	case nil:
+	case ErrSkipChildren:
+		return nil
+	default:
+		return e
+	}
+
+	// Walk through children.
+	children, e := childRefs(r, v)
+	if e != nil {
+		return e
+	}
+	for _, child := range children {
+		if e := w.walkObj(r, path, child, visit); e != nil {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package skywalker
+
+import (
+	"github.com/skycoin/cxo/skyobject"
+	"testing"
+)
+
+func TestWalker_Walk(t *testing.T) {
+	t.Run("all objects", func(t *testing.T) {
+		client, w := newTestWalker(t)
+		defer client.Close()
+
+		count := make(map[string]int)
+		e := w.Walk(func(path Path, s skyobject.Schema, v *skyobject.Value, depth int) error {
+			if depth != len(path)-1 {
+				t.Error("depth", depth, "does not match path", path)
+			}
+			count[s.Name()]++
+			t.Log(path)
+			return nil
+		})
+		if e != nil {
+			t.Error("walk failed:", e)
+		}
+		// Boards: 2, Threads: 3, Posts: 8 (including featured), Persons: 14.
+		if count["Board"] != 2 || count["Thread"] != 3 || count["Post"] != 8 || count["Person"] != 14 {
+			t.Error("unexpected object counts:", count)
+		}
+	})
+	t.Run("skip children", func(t *testing.T) {
+		client, w := newTestWalker(t)
+		defer client.Close()
+
+		count := 0
+		e := w.Walk(func(path Path, s skyobject.Schema, v *skyobject.Value, depth int) error {
+			count++
+			return ErrSkipChildren
+		})
+		if e != nil {
+			t.Error("walk failed:", e)
+		}
+		if count != 2 {
+			t.Error("expected 2 visited objects, got", count)
+		}
+	})
+	t.Run("stop walk", func(t *testing.T) {
+		client, w := newTestWalker(t)
+		defer client.Close()
+
+		count := 0
+		e := w.Walk(func(path Path, s skyobject.Schema, v *skyobject.Value, depth int) error {
+			count++
+			return ErrStopWalk
+		})
+		if e != nil {
+			t.Error("walk failed:", e)
+		}
+		if count != 1 {
+			t.Error("expected 1 visited object, got", count)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package skywalker
+
+import (
+	"context"
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+)
+
+// WatchFunc is called by Watch when the object at a watched path changes. 'oldV' is the object before the change, and
+// 'newV' is the object after the change. Either is nil if the path does not resolve to an object.
+type WatchFunc func(oldV, newV *skyobject.Value)
+
+// Watch watches the object at 'path' for changes. The path is resolved in the walker's root, and is re-resolved in
+// every new version of the root received from 'roots' (e.g. as received by a subscription to the root's feed).
+// 'fn' is called only when the reference of the object at the path changes. If the walker verifies root signatures,
+// roots with bad signatures are ignored.
+// Watch blocks until 'ctx' is done or 'roots' is closed, and does not change the walker.
+func (w *RootWalker) Watch(ctx context.Context, roots <-chan *node.Root, path Path, fn WatchFunc) error {
+	// Check root.
+	r, e := w.root()
+	if e != nil {
+		return e
+	}
+
+	// Helper function. Resolves the path in root 'r', with empty results if it does not resolve.
+	resolve := func(r *node.Root) (skyobject.Dynamic, *skyobject.Value) {
+		dyn, v, e := w.resolvePath(r, path)
+		if e != nil {
+			return skyobject.Dynamic{}, nil
+		}
+		return dyn, v
+	}
+	oldDyn, oldV := resolve(r)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case nr, ok := <-roots:
+			if ok == false {
+				return nil
+			}
+			if nr == nil {
+				continue
+			}
+			if w.verifySig {
+				if e := w.verifyRoot(nr); e != nil {
+					continue
+				}
+			}
+			newDyn, newV := resolve(nr)
+			if newDyn == oldDyn {
+				continue
+			}
+			fn(oldV, newV)
+			oldDyn, oldV = newDyn, newV
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package skywalker
+
+import (
+	"context"
+	"github.com/skycoin/cxo/node"
+	"github.com/skycoin/cxo/skyobject"
+	"testing"
+)
+
+func TestWalker_Watch(t *testing.T) {
+	client, w := newTestWalker(t)
+	defer client.Close()
+	c, pk := client.Container(), w.rpk
+
+	// The watcher has it's own version of the root, as the root of 'w' is changed by it's mutations.
+	watcher, _ := NewReadOnlyRootWalker(c.LastRoot(pk), pk)
+
+	board, thread := &Board{}, &Thread{}
+	advanceToThread(t, w, "Talk", "Expressions", board, thread)
+	path := w.Path()
+
+	roots := make(chan *node.Root)
+	changes := make(chan [2]*skyobject.Value, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watcher.Watch(ctx, roots, path, func(oldV, newV *skyobject.Value) {
+			changes <- [2]*skyobject.Value{oldV, newV}
+		})
+	}()
+
+	// Unchanged path. Each new version of the root is sent as a distinct root, as received from a feed.
+	w.Retreat()
+	if e := w.AppendToRefsField("Threads", Thread{Name: "Unrelated"}); e != nil {
+		t.Error("append failed:", e)
+	}
+	roots <- c.LastRoot(pk)
+
+	// Changed path.
+	if e := w.Restore(path, board, thread); e != nil {
+		t.Fatal("restore failed:", e)
+	}
+	if _, e := w.ReplaceInRefField("Creator", Person{"Donald Trump", 70}); e != nil {
+		t.Error("failed to replace:", e)
+	}
+	roots <- c.LastRoot(pk)
+
+	cancel()
+	if e := <-done; e != context.Canceled {
+		t.Error("expected error", context.Canceled, "got", e)
+	}
+	if len(changes) != 1 {
+		t.Fatal("expected 1 change, got", len(changes))
+	}
+	change := <-changes
+	if change[0] == nil || change[1] == nil {
+		t.Error("expected old and new values")
+	}
+}
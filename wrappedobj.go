@@ -3,7 +3,6 @@ package skywalker
 import (
 	"github.com/skycoin/cxo/skyobject"
 	"reflect"
-	"strings"
 )
 
 type wrappedObj struct {
@@ -11,7 +10,8 @@ type wrappedObj struct {
 	next *wrappedObj
 
 	s skyobject.SchemaReference
-	p interface{}
+	p interface{}      // Deserialized object. Nil if advanced to lazily, and not yet decoded.
+	v *skyobject.Value // Value of object. Nil once the object is re-saved, until it is obtained again with value.
 
 	prevFieldName    string // Field name of prev obj used to find current.
 	prevInFieldIndex int    // Index of prev obj's field's prevInFieldIndex. -1 if single reference (not array).
@@ -19,23 +19,32 @@ type wrappedObj struct {
 	w *RootWalker // Back reference.
 }
 
-func (w *RootWalker) newObj(s skyobject.SchemaReference, p interface{}, fn string, i int) *wrappedObj {
+func (w *RootWalker) newObj(v *skyobject.Value, p interface{}, fn string, i int) *wrappedObj {
 	return &wrappedObj{
-		s:                s,
+		s:                v.Schema().Reference(),
 		p:                p,
+		v:                v,
 		prevFieldName:    fn,
 		prevInFieldIndex: i,
 		w: w,
 	}
 }
 
-func (o *wrappedObj) generate(s skyobject.SchemaReference, p interface{}, fn string, i int) *wrappedObj {
-	newO := o.w.newObj(s, p, fn, i)
+func (o *wrappedObj) generate(v *skyobject.Value, p interface{}, fn string, i int) *wrappedObj {
+	newO := o.w.newObj(v, p, fn, i)
 	newO.prev = o
 	o.next = newO
 	return newO
 }
 
+// Obtains the depth of the object in the internal stack.
+func (o *wrappedObj) depth() (d int) {
+	for prev := o.prev; prev != nil; prev = prev.prev {
+		d++
+	}
+	return
+}
+
 func (o *wrappedObj) elem() reflect.Value {
 	return reflect.ValueOf(o.p).Elem()
 }
@@ -43,6 +52,15 @@ func (o *wrappedObj) elem() reflect.Value {
 func (o *wrappedObj) getFieldAsReferences(fieldName string) (
 	refs skyobject.References, schemaName string, e error,
 ) {
+	if o.p == nil {
+		var fv *skyobject.Value
+		if fv, schemaName, e = o.getValueField(fieldName, referencesField); e != nil {
+			return
+		}
+		e = o.w.deserialize(fv.Data(), &refs)
+		return
+	}
+
 	v := o.elem()
 	vt := v.Type()
 
@@ -59,8 +77,7 @@ func (o *wrappedObj) getFieldAsReferences(fieldName string) (
 	}
 
 	// Obtain schemaName from field tag.
-	fStr := ft.Tag.Get("skyobject")
-	schemaName = strings.TrimPrefix(fStr, "schema=")
+	schemaName = schemaNameFromTag(ft.Tag)
 
 	// Obtain field value.
 	f := v.FieldByName(fieldName)
@@ -71,6 +88,15 @@ func (o *wrappedObj) getFieldAsReferences(fieldName string) (
 func (o *wrappedObj) getFieldAsReference(fieldName string) (
 	ref skyobject.Reference, schemaName string, e error,
 ) {
+	if o.p == nil {
+		var fv *skyobject.Value
+		if fv, schemaName, e = o.getValueField(fieldName, referenceField); e != nil {
+			return
+		}
+		e = o.w.deserialize(fv.Data(), &ref)
+		return
+	}
+
 	v := o.elem()
 	vt := v.Type()
 
@@ -87,8 +113,7 @@ func (o *wrappedObj) getFieldAsReference(fieldName string) (
 	}
 
 	// Obtain schemaName from field tag.
-	fStr := ft.Tag.Get("skyobject")
-	schemaName = strings.TrimPrefix(fStr, "schema=")
+	schemaName = schemaNameFromTag(ft.Tag)
 
 	// Obtain field value.
 	f := v.FieldByName(fieldName)
@@ -99,6 +124,15 @@ func (o *wrappedObj) getFieldAsReference(fieldName string) (
 func (o *wrappedObj) getFieldAsDynamic(fieldName string) (
 	dyn skyobject.Dynamic, e error,
 ) {
+	if o.p == nil {
+		var fv *skyobject.Value
+		if fv, _, e = o.getValueField(fieldName, dynamicField); e != nil {
+			return
+		}
+		e = o.w.deserialize(fv.Data(), &dyn)
+		return
+	}
+
 	v := o.elem()
 	vt := v.Type()
 
@@ -120,6 +154,135 @@ func (o *wrappedObj) getFieldAsDynamic(fieldName string) (
 	return
 }
 
+// Obtains the kind of field 'fieldName' of the object.
+// Uses the schema of the object if it is not deserialized.
+func (o *wrappedObj) getFieldKind(fieldName string) (kind fieldKind, e error) {
+	if o.p == nil {
+		v, e := o.value()
+		if e != nil {
+			return kind, e
+		}
+		for _, f := range v.Schema().Fields() {
+			if f.Name() == fieldName {
+				return kindOfField(f), nil
+			}
+		}
+		return kind, ErrFieldNotFound
+	}
+
+	// Obtain field.
+	ft, has := o.elem().Type().FieldByName(fieldName)
+	if has == false {
+		e = ErrFieldNotFound
+		return
+	}
+
+	switch ft.Type.Kind() {
+	case reflect.Slice: // skyobject.References
+		kind = referencesField
+	case reflect.Array: // skyobject.Reference
+		kind = referenceField
+	case reflect.Struct: // skyobject.Dynamic
+		kind = dynamicField
+	default:
+		kind = plainField
+	}
+	return
+}
+
+// Obtains the value of field 'fieldName' from the value of the object, along with the schema name from it's tag.
+// Used when the object is not deserialized.
+func (o *wrappedObj) getValueField(fieldName string, kind fieldKind) (
+	fv *skyobject.Value, schemaName string, e error,
+) {
+	v, e := o.value()
+	if e != nil {
+		return
+	}
+	for _, f := range v.Schema().Fields() {
+		if f.Name() != fieldName {
+			continue
+		}
+		if kindOfField(f) != kind {
+			e = ErrFieldHasWrongType
+			return
+		}
+		fv, e = v.FieldByName(fieldName)
+		schemaName = schemaNameFromTag(f.Tag())
+		return
+	}
+	e = ErrFieldNotFound
+	return
+}
+
+func (o *wrappedObj) getChild(fieldName string, i int) (
+	dyn skyobject.Dynamic, e error,
+) {
+	kind, e := o.getFieldKind(fieldName)
+	if e != nil {
+		return
+	}
+
+	switch kind {
+	case referencesField:
+		refs, schemaName, e := o.getFieldAsReferences(fieldName)
+		if e != nil {
+			return dyn, e
+		}
+		if i < 0 || i >= len(refs) {
+			return dyn, ErrObjNotFound
+		}
+		schema, e := o.w.r.SchemaByName(schemaName)
+		if e != nil {
+			return dyn, e
+		}
+		dyn = skyobject.Dynamic{Object: refs[i], Schema: schema.Reference()}
+	case referenceField:
+		ref, schemaName, e := o.getFieldAsReference(fieldName)
+		if e != nil {
+			return dyn, e
+		}
+		schema, e := o.w.r.SchemaByName(schemaName)
+		if e != nil {
+			return dyn, e
+		}
+		dyn = skyobject.Dynamic{Object: ref, Schema: schema.Reference()}
+	case dynamicField:
+		dyn, e = o.getFieldAsDynamic(fieldName)
+	default:
+		e = ErrFieldHasWrongType
+	}
+	return
+}
+
+// Obtains the current dynamic reference of the object, as held by the previous object (or the root).
+func (o *wrappedObj) dynamic() (dyn skyobject.Dynamic, e error) {
+	if o.prev == nil {
+		rDyns := o.w.r.Refs()
+		if o.prevInFieldIndex < 0 || o.prevInFieldIndex >= len(rDyns) {
+			e = ErrObjNotFound
+			return
+		}
+		return rDyns[o.prevInFieldIndex], nil
+	}
+	return o.prev.getChild(o.prevFieldName, o.prevInFieldIndex)
+}
+
+// Obtains the current value of the object. As the value obtained when advanced to is dropped once the object is
+// re-saved, it is then obtained from the root by the object's current dynamic reference.
+func (o *wrappedObj) value() (*skyobject.Value, error) {
+	if o.v == nil {
+		dyn, e := o.dynamic()
+		if e != nil {
+			return nil, e
+		}
+		if o.v, e = o.w.valueByDynamic(o.w.r, dyn); e != nil {
+			return nil, e
+		}
+	}
+	return o.v, nil
+}
+
 func (o *wrappedObj) getSchema(ct *skyobject.Container) skyobject.Schema {
 	s, _ := ct.CoreRegistry().SchemaByReference(o.s)
 	return s
@@ -185,63 +348,75 @@ func (o *wrappedObj) replaceDynamicField(fieldName string, newDyn skyobject.Dyna
 	return
 }
 
-func (o *wrappedObj) save() (skyobject.Dynamic, error) {
+// Sets the child of index 'i' of field 'fieldName' to the object of 'dyn'.
+// Index 'i' is ignored for fields of type 'skyobject.Reference' and 'skyobject.Dynamic'.
+func (o *wrappedObj) setChild(fieldName string, i int, dyn skyobject.Dynamic) error {
+	kind, e := o.getFieldKind(fieldName)
+	if e != nil {
+		return e
+	}
+
+	switch kind {
+	case referencesField:
+		refs, _, e := o.getFieldAsReferences(fieldName)
+		if e != nil {
+			return e
+		}
+		if i < 0 || i >= len(refs) {
+			return ErrObjNotFound
+		}
+		refs[i] = dyn.Object
+		return o.replaceReferencesField(fieldName, refs)
+	case referenceField:
+		return o.replaceReferenceField(fieldName, dyn.Object)
+	case dynamicField:
+		return o.replaceDynamicField(fieldName, dyn)
+	default:
+		return ErrFieldHasWrongType
+	}
+}
+
+// Saves the object, and recursively saves it's ancestors up to the root. Propagation stops at the first object whose
+// new reference equals the reference held by it's parent (or the root), as nothing above it changes. In that case,
+// 'changed' is false.
+func (o *wrappedObj) save() (dyn skyobject.Dynamic, changed bool, e error) {
 	// Create dynamic reference of current object.
-	dyn := skyobject.Dynamic{
-		Object: o.w.r.Save(o.p),
-		Schema: o.s,
+	ref, e := o.w.save(o.p, o.depth())
+	if e != nil {
+		return
 	}
+	dyn = skyobject.Dynamic{Object: ref, Schema: o.s}
 
 	// If this object is the direct child of root, save to root and return.
 	if o.prev == nil {
 		r := o.w.r
 		rDyns := r.Refs()
+		if rDyns[o.prevInFieldIndex] == dyn {
+			return dyn, false, nil
+		}
+		o.v = nil
+		o.w.displace(rDyns[o.prevInFieldIndex])
 		rDyns[o.prevInFieldIndex] = dyn
-		r.Replace(rDyns)
-		return dyn, nil
+		o.w.replace(rDyns)
+		return dyn, true, nil
 	}
 
-	// Get previous object's field type.
-	v := o.prev.elem()
-	vt := v.Type()
-
-	sf, has := vt.FieldByName(o.prevFieldName)
-	if has == false {
-		return dyn, ErrFieldNotFound
+	// Obtain previous object's reference of this object. Stop if unchanged.
+	old, e := o.prev.getChild(o.prevFieldName, o.prevInFieldIndex)
+	if e != nil {
+		return dyn, false, e
+	}
+	if old.Object == dyn.Object {
+		return dyn, false, nil
 	}
+	o.v = nil
+	o.w.displace(old)
 
-	switch sf.Type.Kind() {
-	case reflect.Slice: // skyobject.References
-		tRefs, _, e := o.prev.getFieldAsReferences(o.prevFieldName)
-		if e != nil {
-			return dyn, e
-		}
-		tRefs[o.prevInFieldIndex] = dyn.Object
-		e = o.prev.replaceReferencesField(o.prevFieldName, tRefs)
-		if e != nil {
-			return dyn, e
-		}
-	case reflect.Array: // skyobject.Reference
-		tRef, _, e := o.prev.getFieldAsReference(o.prevFieldName)
-		if e != nil {
-			return dyn, e
-		}
-		tRef = dyn.Object
-		e = o.prev.replaceReferenceField(o.prevFieldName, tRef)
-		if e != nil {
-			return dyn, e
-		}
-	case reflect.Struct: // skyobject.Dynamic
-		tDyn, e := o.prev.getFieldAsDynamic(o.prevFieldName)
-		if e != nil {
-			return dyn, e
-		}
-		tDyn = dyn
-		e = o.prev.replaceDynamicField(o.prevFieldName, tDyn)
-		if e != nil {
-			return dyn, e
-		}
+	// Edit previous object.
+	if e = o.prev.setChild(o.prevFieldName, o.prevInFieldIndex, dyn); e != nil {
+		return dyn, false, e
 	}
 
-	return o.prev.save()
+	_, changed, e = o.prev.save()
+	return dyn, changed, e
 }